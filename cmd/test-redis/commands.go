@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"test-redis/internal/config"
+)
+
+// runCommand Выполняет служебную команду, переданную после флагов:
+//
+//	test-redis --config=./config/local.yaml migrate up
+//
+// Команда config выполняется отдельно, до загрузки конфигурации (см. runConfig)
+func runCommand(cfg *config.Config, log *slog.Logger, args []string) error {
+	switch args[0] {
+	case "migrate":
+		return runMigrate(cfg, log, args[1:])
+	case "seed":
+		return runSeed(cfg, log, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q (available: config, migrate, seed)", args[0])
+	}
+}
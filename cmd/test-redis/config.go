@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"test-redis/internal/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+const configUsage = "usage: config check [-print]"
+
+// runConfig Команда config: проверка файла конфигурации без запуска сервиса. Выполняется до загрузки конфигурации
+// (MustLoad завершает работу при ошибке), поэтому пишет в stdout/stderr, а не в лог:
+//
+//	test-redis --config=./config/prod.yaml config check -print
+func runConfig(configPath string, args []string) error {
+	if len(args) == 0 || args[0] != "check" {
+		return errors.New(configUsage)
+	}
+
+	fs := flag.NewFlagSet("config check", flag.ContinueOnError)
+	printCfg := fs.Bool("print", false, "print the effective config (file + environment variables, secrets redacted)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if configPath == "" {
+		return errors.New("config path is empty (use --config or CONFIG_PATH)")
+	}
+
+	// Проверяется то же, что и при запуске: файл, переменные окружения TEST_REDIS_* и значения параметров
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("config %s is valid\n", configPath)
+
+	if *printCfg {
+		tree, err := cfg.Redacted()
+		if err != nil {
+			return err
+		}
+
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(tree); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	return nil
+}
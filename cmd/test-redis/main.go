@@ -2,24 +2,41 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/go-chi/chi/v5"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"test-redis/internal/auth"
+	"test-redis/internal/cache"
+	"test-redis/internal/cache/memoryCache"
+	"test-redis/internal/cache/noopCache"
 	"test-redis/internal/cache/redisCache"
 	"test-redis/internal/config"
+	resp "test-redis/internal/lib/api/response"
 	"test-redis/internal/lib/logger/sl"
+	"test-redis/internal/lib/metrics"
+	"test-redis/internal/lib/retry"
 	"test-redis/internal/storage/sqlite"
 	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	mwAuthenticate "test-redis/internal/http-server/middleware/authenticate"
+	mwBasicAuth "test-redis/internal/http-server/middleware/basicauth"
+	mwCORS "test-redis/internal/http-server/middleware/cors"
 	mwLogger "test-redis/internal/http-server/middleware/logger"
+	mwMetrics "test-redis/internal/http-server/middleware/metrics"
+	mwRateLimit "test-redis/internal/http-server/middleware/ratelimit"
 
 	"test-redis/internal/http-server/handlers"
+	"test-redis/internal/http-server/handlers/admin"
+	"test-redis/internal/http-server/handlers/health"
 )
 
 const (
@@ -28,6 +45,9 @@ const (
 	envProd  = "prod"
 )
 
+// startupPolicyFail Политика запуска: не запускаться, если кэш недоступен (иначе - работать без него)
+const startupPolicyFail = "fail"
+
 func main() {
 	//region // Сервер для тестов с использованием стандартного http
 	//http.HandleFunc("/", handler)
@@ -37,36 +57,80 @@ func main() {
 	//endregion
 
 	//region Загружаем конфигурацию
-	cfg := config.MustLoadFetchFlag() // ...или с использованием параметра командной строки
+	configPath := config.FetchConfigPath() // ...или с использованием параметра командной строки
+
+	// Проверка конфигурации (config check) выполняется до загрузки, чтобы показать все ошибки, а не завершиться на первой
+	if args := flag.Args(); len(args) > 0 && args[0] == "config" {
+		if err := runConfig(configPath, args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfg := config.MustLoadPath(configPath)
 	fmt.Println("time=", time.Now(), "Конфигурация загружена успешно")
 	//endregion
 
 	//region Создаем логгер
 	fmt.Println("time=", time.Now(), "Создание логгера")
-	log := setupLogger(cfg.Env)
+	logLevel := new(slog.LevelVar) // Уровень можно поменять без перезапуска (log_level, SIGHUP)
+	logLevel.Set(logLevelFor(cfg))
+	log := setupLogger(cfg.Env, logLevel)
 	//добавим параметр env с помощью метода log.With
 	log = log.With(slog.String("env", cfg.Env)) // к каждому сообщению будет добавляться поле с информацией о текущем окружении
 	log.Debug("logger debug mode enabled")
 	//endregion
 
-	//region Создаем объект кэша Redis
-	log.Info("initializing cache", slog.String("address", cfg.Cache.Address)) // Помимо сообщения выведем параметр с адресом
-	cache, err := redisCache.NewCache(cfg.Cache.Address, cfg.Cache.Password, cfg.Cache.DB)
-	if err != nil {
-		log.Error("failed to initialize cache", sl.Err(err))
-	} else {
-		log.Info("cache created")
+	//region Служебные команды (migrate, seed) выполняются вместо запуска сервера
+	if args := flag.Args(); len(args) > 0 {
+		if err := runCommand(cfg, log, args); err != nil {
+			log.Error("command failed", slog.String("command", args[0]), sl.Err(err))
+			os.Exit(1)
+		}
+		return
+	}
+	//endregion
+
+	//region Перечитывание конфигурации (SIGHUP): применяются параметры с тегом reload:"true", см. config.Reloader
+	reloader := config.NewReloader(cfg)
+	//endregion
+
+	//region Создаем объект кэша
+	log.Info("initializing cache", slog.String("type", cfg.Cache.Type)) // Помимо сообщения выведем параметр с типом кэша
+	cacheClient := setupCache(cfg.Cache, log)
+	if cfg.Cache.Type == cache.TypeRedis {
+		if err := waitForCache(cfg.Cache, cacheClient, log); err != nil {
+			if cfg.Cache.StartupPolicy == startupPolicyFail {
+				log.Error("cache is unavailable, stopping", sl.Err(err))
+				os.Exit(1)
+			}
+			log.Warn("cache is unavailable, running degraded", sl.Err(err))
+		}
 	}
 	//endregion
 
 	//region Создаем объект Storage Sqlite 3
 	log.Info("initializing storage", slog.String("storage_path", cfg.StoragePath)) // Помимо сообщения выведем параметр с адресом
-	storage, err := sqlite.NewStorage(cfg.StoragePath, cache)
+	storage, err := newStorage(cfg, cacheClient, log)
 	if err != nil {
 		log.Error("failed to initialize storage", sl.Err(err))
-	} else {
-		log.Info("storage created")
+		os.Exit(1)
+	}
+	log.Info("storage created")
+
+	// Рейтинг статей в кэше мог устареть или отсутствовать (новый Redis, изменения БД в обход сервиса)
+	if err := storage.SyncRatings(context.Background()); err != nil {
+		log.Warn("failed to sync ratings", sl.Err(err))
 	}
+
+	// Перенос просмотров статей из кэша в БД
+	flusherCtx, stopFlusher := context.WithCancel(context.Background())
+	flusherDone := make(chan struct{})
+	go func() {
+		defer close(flusherDone)
+		storage.RunViewsFlusher(flusherCtx, cfg.ViewsFlushInterval)
+	}()
 	//endregion
 
 	//region Создаем роутер
@@ -74,23 +138,30 @@ func main() {
 
 	// Настраиваем CORS (предварительно скачиваем пакет: go get github.com/go-chi/cors)
 	// Дополнительная ссылка: https://developer.github.com/v3/#cross-origin-resource-sharing
-	router.Use(cors.Handler(cors.Options{
-		AllowedOrigins: []string{"https://*", "http://*"}, // пока что разрешаем все
+	corsMiddleware := mwCORS.New(log, cors.Options{
+		AllowedOrigins: cfg.HTTPServer.CORS.AllowedOrigins, // по умолчанию разрешаем все
 		//AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		//AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"}, // Authorization - для JWT-токена
 		//ExposedHeaders:   []string{"Link"},
 		//AllowCredentials: false,
 		//MaxAge:           300, // Maximum value not ignored by any of major browsers
-	}))
+	})
+	router.Use(corsMiddleware.Handler)
 
 	// По умолчанию middleware.Logger использует свой собственный внутренний логгер,
 	// который желательно переопределить, чтобы использовался наш,
 	// иначе могут возникнуть проблемы — например, со сбором логов.
 	// Либо можно написать собственный middleware для логирования запросов. Так и сделаем
 	router.Use(middleware.RequestID) // Добавляет request_id в каждый запрос, для трейсинга
+	router.Use(mwMetrics.New())      // Метрики HTTP-запросов для Prometheus
 	router.Use(middleware.Logger)    // Логирование всех запросов. Желательно написать собственный
 	router.Use(mwLogger.New(log))    // Собственный middleware для логирования запросов
 	router.Use(middleware.Recoverer) // Если где-то внутри сервера (обработчика запроса) произойдет паника, приложение не должно упасть
+	tokens := auth.NewTokens(cfg.AppSecret, cfg.HTTPServer.TokenTTL, cacheClient)
+	rateLimiter := setupRateLimit(cfg.HTTPServer.RateLimit, cacheClient, tokens, log)
+	if rateLimiter != nil {
+		router.Use(rateLimiter.Handler) // Ограничение частоты запросов
+	}
 	router.Use(middleware.URLFormat) // Парсер url поступающих запросов
 
 	// Прописываем маршруты с параметром {article_id}.
@@ -100,9 +171,70 @@ func main() {
 
 	router.Get("/article/{article_id}", article.GetArticle(log, storage))
 	router.Get("/articles", article.GetRandArticles(log, storage))
+	router.Get("/articles/list", article.ListArticles(log, storage))
+	router.Get("/search", article.Search(log, storage))
+	router.Get("/trending", article.GetTrending(log, storage))
+	router.Get("/top", article.GetTop(log, storage))
+	router.Get("/article/{article_id}/views", article.GetViews(log, storage))
+	router.Get("/article/{article_id}/comments", article.GetComments(log, storage))
+
+	// Аутентификация: токен выдается по учетным данным из конфигурации (http_server.user / password)
+	router.Post("/auth/login", article.Login(log, auth.StaticUser{User: cfg.HTTPServer.User, Password: cfg.HTTPServer.Password}, tokens))
+
+	// Изменение данных - только с токеном
+	router.Group(func(r chi.Router) {
+		r.Use(mwAuthenticate.New(log, tokens))
+
+		r.Post("/auth/logout", article.Logout(log, tokens))
+		r.Post("/articles", article.SaveArticle(log, storage))
+		r.Put("/article/{article_id}", article.ReplaceArticle(log, storage))
+		r.Patch("/article/{article_id}", article.PatchArticle(log, storage))
+		r.Delete("/article/{article_id}", article.DeleteArticle(log, storage))
+		r.Post("/article/{article_id}/comments", article.SaveComment(log, storage))
+		r.Delete("/article/{article_id}/comments/{comment_id}", article.DeleteComment(log, storage))
+	})
 	//router.Get("/articles", article.GetTestData(log))
+
+	// Служебные маршруты - под Basic-аутентификацией
+	router.Route("/admin", func(r chi.Router) {
+		setupAdmin(r, reloader, cacheClient, storage, log)
+	})
+
+	// Проверки состояния: БД критична всегда, кэш - только если без него сервис не должен работать
+	healthComponents := []health.Component{
+		{Name: "sqlite", Check: storage.Ping, Critical: true},
+		{Name: cfg.Cache.Type, Check: cacheClient.Ping, Critical: cfg.Cache.StartupPolicy == startupPolicyFail},
+	}
+	router.Handle("/metrics", metrics.Default.Handler())
+	router.Get("/healthz", health.Liveness(log, healthComponents...))
+	router.Get("/readyz", health.Readiness(log, healthComponents...))
 	router.Get("/test", article.GetTestData(log))
 	router.Get("/users/{user_id}", article.GetUserById(log))
+
+	// Ответы на неизвестные маршруты - в том же формате, что и остальные ответы API
+	router.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		resp.Error(w, r, http.StatusNotFound, "route not found")
+	})
+	router.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		resp.Error(w, r, http.StatusMethodNotAllowed, "method not allowed")
+	})
+	//endregion
+
+	//region Применение перечитанной конфигурации
+	reloader.OnReload(func(cfg *config.Config) {
+		logLevel.Set(logLevelFor(cfg))
+		if updater, ok := cacheClient.(cache.TTLUpdater); ok {
+			updater.SetTTLPolicy(ttlPolicy(cfg.Cache))
+		}
+		storage.SetNegativeTTL(cfg.Cache.NegativeTTL)
+		if rateLimiter != nil {
+			rateLimiter.Update(rateLimitOptions(cfg.HTTPServer.RateLimit, tokens))
+		}
+		corsMiddleware.SetAllowedOrigins(cfg.HTTPServer.CORS.AllowedOrigins)
+	})
+	reloadCtx, stopReload := context.WithCancel(context.Background())
+	defer stopReload()
+	go watchReload(reloadCtx, reloader, log)
 	//endregion
 
 	//region ЗАПУСК и ОСТАНОВКА СЕРВЕРА
@@ -119,53 +251,249 @@ func main() {
 		IdleTimeout:  cfg.HTTPServer.IdleTimeout,
 	}
 
+	serverErr := make(chan error, 1)
 	go func() {
-		if err := srv.ListenAndServe(); err != nil {
-			if err.Error() != "http: Server closed" {
-				log.Error("failed to start server", slog.String("error", err.Error()))
-			}
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
 		}
 	}()
 	log.Info("server started")
 
-	// ждем, пока в канал не придет сигнал с остановкой сервера
-	<-done
-	log.Info("stopping server")
+	// ждем, пока в канал не придет сигнал с остановкой сервера (или сервер не упадет сам)
+	var failed bool
+	select {
+	case <-done:
+	case err := <-serverErr:
+		log.Error("failed to start server", sl.Err(err))
+		failed = true
+	}
+	log.Info("stopping server", slog.String("timeout", cfg.HTTPServer.ShutdownTimeout.String()))
 
-	// TODO: move timeout to config
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// Остановка идет по порядку: сначала перестаем принимать запросы и дожидаемся текущих,
+	// затем останавливаем фоновые задачи (они переносят накопленные просмотры в БД),
+	// и только после этого закрываем кэш и хранилище. Ошибка на одном шаге не отменяет следующие
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTPServer.ShutdownTimeout)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Error("failed to stop server", sl.Err(err))
-		return
+		log.Error("failed to stop server gracefully, closing connections", sl.Err(err))
+		srv.Close()
 	}
+	log.Info("http server stopped")
 
-	// TODO: close storage
-	//...
+	// Новых просмотров больше не будет - переносим накопленные в БД
+	stopFlusher()
+	select {
+	case <-flusherDone:
+		log.Info("views flushed")
+		closeResources(cacheClient, storage, log)
+	case <-ctx.Done():
+		// Перенос еще идет: если закрыть кэш и БД, снятые из кэша просмотры не попадут в БД и будут потеряны.
+		// Соединения закроются вместе с процессом
+		log.Error("views flusher did not stop in time, cache and storage are left open", sl.Err(ctx.Err()))
+	}
 
 	log.Info("server stopped")
+	if failed {
+		os.Exit(1)
+	}
 	//endregion
 
 }
 
-// setupLogger Создает логгер в зависимости от окружения с разными параметрами — TextHandler / JSONHandler и уровень LevelDebug / LevelInfo
-func setupLogger(env string) *slog.Logger {
+// setupLogger Создает логгер в зависимости от окружения с разными параметрами — TextHandler / JSONHandler.
+// Уровень логирования задается level и может меняться во время работы
+func setupLogger(env string, level slog.Leveler) *slog.Logger {
 	var log *slog.Logger
 
 	switch env {
 	case envLocal:
-		log = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
-	case envDev:
-		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
-	case envProd:
-		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		log = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
+	case envDev, envProd:
+		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
 	default: // If env config is invalid, set prod settings by default due to security
 		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}),
+			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level}),
 		)
 
 	}
 
 	return log
 }
+
+// logLevelFor Уровень логирования из конфигурации (log_level), а если он не задан - по окружению: LevelDebug для dev, иначе LevelInfo
+func logLevelFor(cfg *config.Config) slog.Level {
+	var level slog.Level
+	if cfg.LogLevel != "" && level.UnmarshalText([]byte(cfg.LogLevel)) == nil {
+		return level
+	}
+
+	if cfg.Env == envDev {
+		return slog.LevelDebug
+	}
+
+	return slog.LevelInfo
+}
+
+// setupCache Создает кэш указанного в конфигурации типа.
+// Если кэш создать не удалось, сервис продолжает работу без кэширования (noopCache)
+func setupCache(cfg config.Cache, log *slog.Logger) cache.Cache {
+	ttl := ttlPolicy(cfg)
+
+	switch cfg.Type {
+	case cache.TypeRedis:
+		c, err := redisCache.NewCache(redisCache.Options{
+			Mode:             cfg.Mode,
+			Address:          cfg.Address,
+			Addresses:        cfg.Addresses,
+			MasterName:       cfg.MasterName,
+			Username:         cfg.Username,
+			Password:         cfg.Password,
+			SentinelPassword: cfg.SentinelPassword,
+			DB:               cfg.DB,
+			PoolSize:         cfg.PoolSize,
+			MinIdleConns:     cfg.MinIdleConns,
+			TLS: redisCache.TLSOptions{
+				Enabled:            cfg.TLS.Enabled,
+				CAFile:             cfg.TLS.CAFile,
+				CertFile:           cfg.TLS.CertFile,
+				KeyFile:            cfg.TLS.KeyFile,
+				ServerName:         cfg.TLS.ServerName,
+				InsecureSkipVerify: cfg.TLS.InsecureSkipVerify,
+			},
+			Prefix:  cfg.Prefix,
+			TTL:     ttl,
+			Timeout: cfg.Timeout,
+		})
+		if err != nil {
+			log.Error("failed to initialize redis cache, caching disabled", sl.Err(err))
+			return noopCache.NewCache()
+		}
+		log.Info("redis cache created", slog.String("mode", cfg.Mode), slog.String("address", cfg.Address), slog.Any("addresses", cfg.Addresses))
+		return c
+	case cache.TypeMemory:
+		log.Info("memory cache created", slog.Int("size", cfg.MemorySize))
+		return memoryCache.NewCache(cfg.MemorySize, ttl)
+	case cache.TypeNone:
+		log.Info("caching disabled")
+		return noopCache.NewCache()
+	default:
+		log.Error("unknown cache type, caching disabled", slog.String("type", cfg.Type))
+		return noopCache.NewCache()
+	}
+}
+
+// waitForCache Проверяет доступность кэша при запуске (PING с повторами и растущей паузой)
+func waitForCache(cfg config.Cache, c cache.Cache, log *slog.Logger) error {
+	return retry.Do(context.Background(), cfg.StartupRetries, cfg.StartupBackoff, c.Ping, func(attempt int, err error) {
+		log.Warn("cache ping failed, retrying", slog.Int("attempt", attempt), sl.Err(err))
+	})
+}
+
+// setupRateLimit Создает middleware ограничения частоты запросов. nil - кэш его не поддерживает.
+// Выключенное в конфигурации ограничение тоже создается (пропускает все запросы), чтобы его можно было включить без перезапуска
+func setupRateLimit(cfg config.RateLimit, cacheClient cache.Cache, tokens *auth.Tokens, log *slog.Logger) *mwRateLimit.Limiter {
+	limiter, ok := cacheClient.(cache.RateLimiter)
+	if !ok {
+		if cfg.Enabled {
+			log.Warn("rate limit is not supported by the cache, requests are not limited")
+		}
+		return nil
+	}
+
+	return mwRateLimit.New(log, limiter, rateLimitOptions(cfg, tokens))
+}
+
+// rateLimitOptions Параметры ограничения частоты запросов из конфигурации
+func rateLimitOptions(cfg config.RateLimit, tokens *auth.Tokens) mwRateLimit.Options {
+	opts := mwRateLimit.Options{
+		Disabled: !cfg.Enabled,
+		Default:  mwRateLimit.Limit{Requests: cfg.Requests, Window: cfg.Window},
+		KeyBy:    cfg.KeyBy,
+		Routes:   make(map[string]mwRateLimit.Limit, len(cfg.Routes)),
+		// Ограничение проверяется до аутентификации, поэтому пользователь берется из токена без проверки отзыва
+		User: func(r *http.Request) string {
+			claims, err := tokens.Parse(mwAuthenticate.BearerToken(r))
+			if err != nil {
+				return ""
+			}
+			return claims.Subject
+		},
+	}
+	// Не заданные для маршрута параметры берутся из общего ограничения
+	for route, limit := range cfg.Routes {
+		l := opts.Default
+		if limit.Requests > 0 {
+			l.Requests = limit.Requests
+		}
+		if limit.Window > 0 {
+			l.Window = limit.Window
+		}
+		opts.Routes[route] = l
+	}
+
+	return opts
+}
+
+// ttlPolicy Правила вычисления времени жизни записей кэша из конфигурации
+func ttlPolicy(cfg config.Cache) cache.TTLPolicy {
+	return cache.TTLPolicy{
+		Default:   cfg.TTL,
+		Overrides: cfg.TTLOverrides,
+		Jitter:    cfg.TTLJitter,
+	}
+}
+
+// setupAdmin Служебные маршруты (управление кэшем, действующая конфигурация) под Basic-аутентификацией с учетными данными из конфигурации.
+// Если кэш не поддерживает просмотр ключей (none), маршруты управления кэшем не подключаются
+func setupAdmin(r chi.Router, reloader *config.Reloader, cacheClient cache.Cache, storage *sqlite.Storage, log *slog.Logger) {
+	cfg := reloader.Current()
+	r.Use(mwBasicAuth.New(log, "admin", auth.StaticUser{User: cfg.HTTPServer.User, Password: cfg.HTTPServer.Password}))
+
+	r.Get("/config", admin.Config(log, reloader))
+	r.Get("/cache/stats", article.GetCacheStats(log, storage))
+
+	inspector, ok := cacheClient.(cache.Inspector)
+	if !ok {
+		log.Warn("cache does not support key inspection, admin cache routes are disabled", slog.String("type", cfg.Cache.Type))
+		return
+	}
+
+	r.Get("/cache/keys", admin.ListKeys(log, inspector))
+	r.Delete("/cache/keys", admin.FlushPrefix(log, inspector, sqlite.FlushableNamespaces))
+	r.Get("/cache/key", admin.InspectKey(log, inspector))
+	r.Get("/cache/ttl", admin.TTLReport(log, inspector, ttlSettings{reloader}))
+}
+
+// closeResources Закрывает кэш (если ему есть что закрывать - например, соединения с Redis), затем хранилище
+func closeResources(cacheClient cache.Cache, storage *sqlite.Storage, log *slog.Logger) {
+	if closer, ok := cacheClient.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Error("failed to close cache", sl.Err(err))
+		} else {
+			log.Info("cache closed")
+		}
+	}
+
+	if err := storage.Close(); err != nil {
+		log.Error("failed to close storage", sl.Err(err))
+	} else {
+		log.Info("storage closed")
+	}
+}
+
+// newStorage Создает хранилище с параметрами кэширования из конфигурации
+func newStorage(cfg *config.Config, cacheClient cache.Cache, log *slog.Logger) (*sqlite.Storage, error) {
+	codec, err := cache.NewCodec(cfg.Cache.Serialization)
+	if err != nil {
+		log.Error("invalid cache serialization, using json", sl.Err(err))
+		codec = cache.JSONCodec{}
+	}
+
+	return sqlite.NewStorage(cfg.StoragePath, cacheClient, sqlite.Options{
+		Codec:       codec,
+		NegativeTTL: cfg.Cache.NegativeTTL,
+		Timeout:     cfg.StorageTimeout,
+		Log:         log,
+	})
+}
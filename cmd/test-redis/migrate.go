@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"test-redis/internal/config"
+	"test-redis/internal/storage/migrator"
+	"test-redis/internal/storage/sqlite"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = "usage: migrate up | down | status | to <version>"
+
+// runMigrate Команда migrate: применение и откат миграций схемы БД
+func runMigrate(cfg *config.Config, log *slog.Logger, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	m, err := sqlite.NewMigrator(cfg.StoragePath)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	var done []migrator.Migration
+	switch args[0] {
+	case "up":
+		done, err = m.Up()
+	case "down":
+		done, err = m.Down()
+	case "to":
+		if len(args) < 2 {
+			return errors.New(migrateUsage)
+		}
+		version, convErr := strconv.Atoi(args[1])
+		if convErr != nil {
+			return fmt.Errorf("invalid version %q: %w", args[1], convErr)
+		}
+		done, err = m.To(version)
+	case "status":
+		return printMigrateStatus(m)
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	// Успешно выполненные миграции выводим даже при ошибке на одной из следующих
+	for _, mig := range done {
+		log.Info("migration done", slog.String("command", args[0]), slog.Int("version", mig.Version), slog.String("name", mig.Name))
+	}
+	if err != nil {
+		return err
+	}
+
+	version, err := m.Version()
+	if err != nil {
+		return err
+	}
+	log.Info("schema is at version", slog.Int("version", version), slog.Int("latest", m.Latest()))
+
+	return nil
+}
+
+// printMigrateStatus Выводит таблицу миграций и их состояние
+func printMigrateStatus(m *migrator.Migrator) error {
+	statuses, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, st := range statuses {
+		status, appliedAt := "pending", "-"
+		if st.Applied {
+			status = "applied"
+			appliedAt = st.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", st.Version, st.Name, status, appliedAt)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"test-redis/internal/cache"
+	"test-redis/internal/config"
+	"test-redis/internal/lib/logger/sl"
+	"time"
+)
+
+// watchReload Перечитывает конфигурацию по сигналу SIGHUP (kill -HUP <pid>), пока не отменен ctx.
+// Ошибки в файле не останавливают сервис: он продолжает работать с прежней конфигурацией
+func watchReload(ctx context.Context, reloader *config.Reloader, log *slog.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		}
+
+		log.Info("reloading config", slog.String("path", reloader.Current().Path))
+
+		ignored, err := reloader.Reload()
+		if err != nil {
+			log.Error("failed to reload config, keeping current", sl.Err(err))
+			continue
+		}
+		if len(ignored) > 0 {
+			// Эти параметры нельзя применить на ходу - действуют прежние значения
+			log.Warn("config changes require restart, ignored", slog.String("fields", strings.Join(ignored, ",")))
+		}
+
+		log.Info("config reloaded")
+	}
+}
+
+// ttlSettings Настройки времени жизни записей кэша из действующей конфигурации (для admin.TTLReport)
+type ttlSettings struct {
+	reloader *config.Reloader
+}
+
+func (s ttlSettings) TTLPolicy() cache.TTLPolicy {
+	return ttlPolicy(s.reloader.Current().Cache)
+}
+
+func (s ttlSettings) NegativeTTL() time.Duration {
+	return s.reloader.Current().Cache.NegativeTTL
+}
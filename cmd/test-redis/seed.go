@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"test-redis/internal/config"
+	"test-redis/internal/storage/sqlite"
+	"time"
+)
+
+// runSeed Команда seed: генерация тестовых статей и комментариев
+//
+//	test-redis --config=./config/local.yaml seed -articles=1000 -comments=50 -seed=42 -warm-cache
+func runSeed(cfg *config.Config, log *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	articles := fs.Int("articles", 100, "number of articles to generate")
+	comments := fs.Int("comments", 99, "number of comments per article")
+	seed := fs.Int64("seed", 1, "random seed (the same seed gives the same data)")
+	batch := fs.Int("batch", 1000, "rows per transaction")
+	truncate := fs.Bool("truncate", false, "delete existing articles and comments first")
+	warmCache := fs.Bool("warm-cache", false, "put generated articles into the cache")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cacheClient := setupCache(cfg.Cache, log)
+	storage, err := newStorage(cfg, cacheClient, log)
+	if err != nil {
+		return err
+	}
+	defer closeResources(cacheClient, storage, log)
+
+	// Генерацию можно прервать (Ctrl+C): незафиксированный пакет откатывается
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	start := time.Now()
+	res, err := storage.Seed(ctx, sqlite.SeedOptions{
+		Articles:           *articles,
+		CommentsPerArticle: *comments,
+		Seed:               *seed,
+		BatchSize:          *batch,
+		Truncate:           *truncate,
+		WarmCache:          *warmCache,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := storage.SyncRatings(ctx); err != nil {
+		log.Warn("failed to sync ratings", slog.String("error", err.Error()))
+	}
+
+	log.Info("seed done",
+		slog.Int("articles", res.Articles),
+		slog.Int("comments", res.Comments),
+		slog.Int("warmed", res.Warmed),
+		slog.String("duration", time.Since(start).String()),
+	)
+
+	return nil
+}
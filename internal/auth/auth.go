@@ -0,0 +1,119 @@
+// internal/auth/auth.go
+
+// Аутентификация: проверка учетных данных, выдача, проверка и отзыв JWT-токенов
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"test-redis/internal/cache"
+	"test-redis/internal/lib/jwt"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token") // Оборачивает все ошибки самого токена (подпись, срок действия, отзыв)
+	ErrRevoked      = errors.New("token is revoked")
+)
+
+// revokedPrefix Пространство имен ключей отозванных токенов в кэше
+const revokedPrefix = "jwt:revoked:"
+
+// StaticUser Единственный пользователь, учетные данные которого заданы в конфигурации
+type StaticUser struct {
+	User     string
+	Password string
+}
+
+// Check Проверяет учетные данные. Сравнение за постоянное время, чтобы по времени ответа нельзя было подобрать пароль
+func (u StaticUser) Check(user string, password string) bool {
+	userOK := subtle.ConstantTimeCompare([]byte(user), []byte(u.User)) == 1
+	passwordOK := subtle.ConstantTimeCompare([]byte(password), []byte(u.Password)) == 1
+
+	return userOK && passwordOK
+}
+
+// Tokens Выдача и проверка токенов. Отозванные токены хранятся в кэше до истечения их срока действия
+type Tokens struct {
+	secret []byte
+	ttl    time.Duration
+	cache  cache.Cache
+}
+
+// NewTokens Конструктор объекта Tokens. secret - ключ подписи (config.AppSecret), ttl - срок действия токена
+func NewTokens(secret string, ttl time.Duration, c cache.Cache) *Tokens {
+	return &Tokens{secret: []byte(secret), ttl: ttl, cache: c}
+}
+
+// Issue Выдать токен пользователю
+func (t *Tokens) Issue(user string) (string, jwt.Claims, error) {
+	const op = "auth.Issue"
+
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", jwt.Claims{}, fmt.Errorf("%s: token id: %w", op, err)
+	}
+
+	now := time.Now()
+	claims := jwt.Claims{
+		ID:        hex.EncodeToString(id[:]),
+		Subject:   user,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(t.ttl).Unix(),
+	}
+
+	token, err := jwt.Sign(claims, t.secret)
+	if err != nil {
+		return "", jwt.Claims{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, claims, nil
+}
+
+// Parse Проверить подпись и срок действия токена (без проверки отзыва)
+func (t *Tokens) Parse(token string) (jwt.Claims, error) {
+	return jwt.Parse(token, t.secret, time.Now())
+}
+
+// Verify Проверить токен, в том числе что он не отозван. Ошибки самого токена (jwt.Err*, ErrRevoked) оборачиваются в ErrInvalidToken,
+// остальные (кэш недоступен) - нет
+func (t *Tokens) Verify(ctx context.Context, token string) (jwt.Claims, error) {
+	const op = "auth.Verify"
+
+	claims, err := t.Parse(token)
+	if err != nil {
+		return jwt.Claims{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	_, err = t.cache.Get(ctx, revokedPrefix+claims.ID)
+	if err == nil {
+		return jwt.Claims{}, fmt.Errorf("%w: %w", ErrInvalidToken, ErrRevoked)
+	}
+	if !errors.Is(err, cache.ErrDataNotFound) {
+		return jwt.Claims{}, fmt.Errorf("%s: check revocation: %w", op, err)
+	}
+
+	return claims, nil
+}
+
+// Revoke Отозвать токен. Запись об отзыве хранится, пока токен не истечет
+func (t *Tokens) Revoke(ctx context.Context, claims jwt.Claims) error {
+	const op = "auth.Revoke"
+
+	// Запись об отзыве живет, пока токен еще может быть принят: оставшийся срок действия плюс допустимое расхождение часов.
+	// Явно заданное время жизни кэш не меняет (без разброса cache.TTLPolicy.Jitter)
+	ttl := time.Until(time.Unix(claims.ExpiresAt, 0).Add(jwt.Leeway))
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := t.cache.Set(ctx, revokedPrefix+claims.ID, []byte("1"), ttl); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"test-redis/internal/cache"
+	"test-redis/internal/cache/memoryCache"
+	"test-redis/internal/lib/jwt"
+)
+
+// failingCache Кэш, все операции которого завершаются ошибкой (Redis недоступен)
+type failingCache struct {
+	cache.Cache
+	err error
+}
+
+func (c failingCache) Get(context.Context, string) ([]byte, error) { return nil, c.err }
+
+func (c failingCache) Set(context.Context, string, []byte, time.Duration) error { return c.err }
+
+func TestTokens_Verify(t *testing.T) {
+	errUnavailable := errors.New("connection refused")
+
+	tests := []struct {
+		name        string
+		revoke      bool
+		cacheErr    error
+		secret      string // ключ, которым подписан токен
+		wantErr     error
+		wantInvalid bool
+	}{
+		{name: "valid", secret: "secret"},
+		{name: "revoked", secret: "secret", revoke: true, wantErr: ErrRevoked, wantInvalid: true},
+		{name: "bad signature", secret: "other", wantErr: jwt.ErrSignature, wantInvalid: true},
+		{name: "cache unavailable", secret: "secret", cacheErr: errUnavailable, wantErr: errUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := memoryCache.NewCache(100, cache.TTLPolicy{})
+			tokens := NewTokens("secret", time.Hour, c)
+
+			token := issue(t, NewTokens(tt.secret, time.Hour, c))
+			if tt.revoke {
+				claims, err := tokens.Parse(token)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := tokens.Revoke(ctx, claims); err != nil {
+					t.Fatalf("Revoke() error = %v", err)
+				}
+			}
+			if tt.cacheErr != nil {
+				tokens = NewTokens("secret", time.Hour, failingCache{err: tt.cacheErr})
+			}
+
+			claims, err := tokens.Verify(ctx, token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := errors.Is(err, ErrInvalidToken); got != tt.wantInvalid {
+				t.Errorf("Verify() error is ErrInvalidToken = %v, want %v", got, tt.wantInvalid)
+			}
+			if err == nil && claims.Subject != "user" {
+				t.Errorf("Verify() subject = %q, want user", claims.Subject)
+			}
+		})
+	}
+}
+
+func TestTokens_RevokeTTL(t *testing.T) {
+	ctx := context.Background()
+	// Разброс времени жизни не должен сокращать запись об отзыве
+	c := memoryCache.NewCache(100, cache.TTLPolicy{Default: time.Second, Jitter: 0.5})
+	tokens := NewTokens("secret", time.Hour, c)
+
+	claims, err := tokens.Parse(issue(t, tokens))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tokens.Revoke(ctx, claims); err != nil {
+		t.Fatal(err)
+	}
+
+	infos, err := c.Inspect(ctx, revokedPrefix+claims.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Оставшийся срок действия токена плюс допустимое расхождение часов
+	want := time.Until(time.Unix(claims.ExpiresAt, 0).Add(jwt.Leeway))
+	if got := infos[0].TTL; got > want+time.Second || got < want-time.Second {
+		t.Errorf("revocation ttl = %v, want %v", got, want)
+	}
+
+	// Истекший токен не отзывается: он и так не будет принят
+	expired := jwt.Claims{ID: "expired", ExpiresAt: time.Now().Add(-time.Hour).Unix()}
+	if err := tokens.Revoke(ctx, expired); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(ctx, revokedPrefix+"expired"); !errors.Is(err, cache.ErrDataNotFound) {
+		t.Errorf("expired token revocation stored, Get() error = %v", err)
+	}
+}
+
+func issue(t *testing.T, tokens *Tokens) string {
+	t.Helper()
+
+	token, _, err := tokens.Issue("user")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	return token
+}
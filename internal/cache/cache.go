@@ -1,7 +1,31 @@
 package cache
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
 var (
 	ErrDataNotFound = errors.New("data not found")
+	ErrEmptyPrefix  = errors.New("empty key prefix") // Удаление по пустому префиксу стерло бы весь кэш (или всю БД Redis без cache.prefix)
+)
+
+// Типы кэша, задаются в конфигурации (cache.type)
+const (
+	TypeRedis  = "redis"  // Redis (redisCache)
+	TypeMemory = "memory" // LRU-кэш в памяти процесса (memoryCache)
+	TypeNone   = "none"   // Кэширование отключено (noopCache)
 )
+
+// Cache Интерфейс кэша. Значения хранятся в виде набора байт, сериализацией занимается вызывающая сторона
+type Cache interface {
+	// Get Получить значение по ключу. Если ключа нет (или истек его срок жизни) - ErrDataNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set Сохранить значение. ttl == 0 - время жизни определяется настройками кэша (TTLPolicy)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete Удалить ключи. Отсутствие ключа ошибкой не считается
+	Delete(ctx context.Context, keys ...string) error
+	// Ping Проверить доступность кэша
+	Ping(ctx context.Context) error
+}
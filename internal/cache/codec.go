@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// Форматы сериализации значений, задаются в конфигурации (cache.serialization)
+const (
+	SerializationJSON = "json"
+	SerializationGob  = "gob"
+)
+
+// Codec Сериализация значений для хранения в кэше
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// NewCodec Возвращает кодек по названию формата
+func NewCodec(name string) (Codec, error) {
+	switch name {
+	case SerializationJSON, "":
+		return JSONCodec{}, nil
+	case SerializationGob:
+		return GobCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unknown serialization format %q", name)
+	}
+}
+
+// JSONCodec Сериализация в JSON - значения удобно смотреть через redis-cli
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// GobCodec Сериализация в gob - компактнее для структур с большим количеством полей
+type GobCodec struct{}
+
+func (GobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
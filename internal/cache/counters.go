@@ -0,0 +1,16 @@
+package cache
+
+import "context"
+
+// Counters Необязательный интерфейс кэша: целочисленные счетчики и множества строк (Redis INCRBY/GETDEL, SADD/SPOP).
+// Значение счетчика можно прочитать и через Get (десятичная строка). Счетчики создаются без ограничения времени жизни
+type Counters interface {
+	// IncrBy Увеличить счетчик на n (отсутствующий счетчик считается нулевым). Возвращает новое значение
+	IncrBy(ctx context.Context, key string, n int64) (int64, error)
+	// GetDel Прочитать и удалить счетчик одной операцией. Отсутствующий счетчик - 0
+	GetDel(ctx context.Context, key string) (int64, error)
+	// SAdd Добавить элементы в множество
+	SAdd(ctx context.Context, key string, members ...string) error
+	// SPop Извлечь (прочитать и удалить) до n случайных элементов множества
+	SPop(ctx context.Context, key string, n int) ([]string, error)
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// KeyInfo Сведения о ключе кэша
+type KeyInfo struct {
+	Key     string        `json:"key"`
+	Exists  bool          `json:"exists"`
+	Type    string        `json:"type,omitempty"`    // string, zset, set
+	TTL     time.Duration `json:"ttl,omitempty"`     // Оставшееся время жизни (0 - без ограничения)
+	Size    int64         `json:"size"`              // Длина значения в байтах (string) или количество элементов (zset, set)
+	Preview string        `json:"preview,omitempty"` // Начало значения (только для string)
+}
+
+// Inspector Необязательный интерфейс кэша: просмотр и удаление ключей (администрирование)
+type Inspector interface {
+	// Scan Ключи, начинающиеся с prefix (не больше limit, limit <= 0 - все). Порядок не определен
+	Scan(ctx context.Context, prefix string, limit int) ([]string, error)
+	// Inspect Сведения о ключах
+	Inspect(ctx context.Context, keys ...string) ([]KeyInfo, error)
+	// DeletePrefix Удалить все ключи, начинающиеся с prefix. Возвращает количество удаленных ключей.
+	// Пустой prefix - ошибка ErrEmptyPrefix
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+}
+
+// PreviewSize Максимальная длина KeyInfo.Preview (в байтах)
+const PreviewSize = 256
+
+// Preview Начало значения для KeyInfo.Preview
+func Preview(value []byte) string {
+	if len(value) > PreviewSize {
+		return string(value[:PreviewSize]) + "…"
+	}
+
+	return string(value)
+}
+
+// EntityTTL Время жизни ключей одной сущности (по выборке ключей)
+type EntityTTL struct {
+	Keys       int           `json:"keys"`
+	NoExpiry   int           `json:"no_expiry"` // Ключи без ограничения времени жизни
+	MinTTL     time.Duration `json:"min_ttl"`
+	MaxTTL     time.Duration `json:"max_ttl"`
+	AverageTTL time.Duration `json:"average_ttl"`
+}
+
+// TTLReport Оставшееся время жизни ключей по сущностям (см. Entity). Смотрится не больше sample ключей с префиксом prefix
+func TTLReport(ctx context.Context, inspector Inspector, prefix string, sample int) (map[string]EntityTTL, error) {
+	keys, err := inspector.Scan(ctx, prefix, sample)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := inspector.Inspect(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	report := make(map[string]EntityTTL)
+	sums := make(map[string]time.Duration)
+	for _, info := range infos {
+		if !info.Exists {
+			continue
+		}
+
+		entity := Entity(info.Key)
+		stats := report[entity]
+		stats.Keys++
+		if info.TTL <= 0 {
+			stats.NoExpiry++
+		} else {
+			if stats.MinTTL == 0 || info.TTL < stats.MinTTL {
+				stats.MinTTL = info.TTL
+			}
+			if info.TTL > stats.MaxTTL {
+				stats.MaxTTL = info.TTL
+			}
+			sums[entity] += info.TTL
+		}
+		report[entity] = stats
+	}
+
+	for entity, stats := range report {
+		if withTTL := stats.Keys - stats.NoExpiry; withTTL > 0 {
+			stats.AverageTTL = sums[entity] / time.Duration(withTTL)
+			report[entity] = stats
+		}
+	}
+
+	return report, nil
+}
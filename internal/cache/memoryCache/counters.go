@@ -0,0 +1,104 @@
+//internal/cache/memoryCache/counters.go
+
+package memoryCache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// IncrBy Увеличить счетчик. Счетчик хранится как обычное значение (десятичная строка) без ограничения срока жизни,
+// поэтому при переполнении кэша может быть вытеснен
+func (c *Cache) IncrBy(_ context.Context, key string, n int64) (int64, error) {
+	const op = "cache.memoryCache.IncrBy"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var value int64
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		if !e.expired(time.Now()) {
+			current, err := strconv.ParseInt(string(e.value), 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("%s: key %s is not a counter: %w", op, key, err)
+			}
+			value = current
+		}
+		c.remove(el)
+	}
+	value += n
+
+	c.items[key] = c.order.PushFront(&entry{key: key, value: []byte(strconv.FormatInt(value, 10))})
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+
+	return value, nil
+}
+
+// GetDel Прочитать и удалить счетчик
+func (c *Cache) GetDel(_ context.Context, key string) (int64, error) {
+	const op = "cache.memoryCache.GetDel"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return 0, nil
+	}
+	c.remove(el)
+
+	e := el.Value.(*entry)
+	if e.expired(time.Now()) {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseInt(string(e.value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: key %s is not a counter: %w", op, key, err)
+	}
+
+	return value, nil
+}
+
+// SAdd Добавить элементы в множество. Множества в ограничение capacity не входят
+func (c *Cache) SAdd(_ context.Context, key string, members ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	set, ok := c.sets[key]
+	if !ok {
+		set = make(map[string]struct{}, len(members))
+		c.sets[key] = set
+	}
+	for _, m := range members {
+		set[m] = struct{}{}
+	}
+
+	return nil
+}
+
+// SPop Извлечь до n элементов множества (порядок обхода map случаен)
+func (c *Cache) SPop(_ context.Context, key string, n int) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	set := c.sets[key]
+	res := make([]string, 0, min(n, len(set)))
+	for m := range set {
+		if len(res) == n {
+			break
+		}
+		res = append(res, m)
+		delete(set, m)
+	}
+	if len(set) == 0 {
+		delete(c.sets, key)
+	}
+
+	return res, nil
+}
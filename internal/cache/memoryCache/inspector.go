@@ -0,0 +1,122 @@
+//internal/cache/memoryCache/inspector.go
+
+package memoryCache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"test-redis/internal/cache"
+	"time"
+)
+
+// Scan Ключи с префиксом (значения, упорядоченные множества и множества)
+func (c *Cache) Scan(_ context.Context, prefix string, limit int) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	var keys []string
+	add := func(key string) bool {
+		if limit > 0 && len(keys) >= limit {
+			return false
+		}
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return true
+	}
+
+	for key, el := range c.items {
+		if el.Value.(*entry).expired(now) {
+			continue
+		}
+		if !add(key) {
+			return keys, nil
+		}
+	}
+	for key := range c.zsets {
+		if c.zset(key, false) == nil {
+			continue
+		}
+		if !add(key) {
+			return keys, nil
+		}
+	}
+	for key := range c.sets {
+		if !add(key) {
+			return keys, nil
+		}
+	}
+
+	return keys, nil
+}
+
+// Inspect Сведения о ключах
+func (c *Cache) Inspect(_ context.Context, keys ...string) ([]cache.KeyInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	infos := make([]cache.KeyInfo, len(keys))
+	for i, key := range keys {
+		info := cache.KeyInfo{Key: key}
+
+		if el, ok := c.items[key]; ok && !el.Value.(*entry).expired(now) {
+			e := el.Value.(*entry)
+			info.Exists, info.Type = true, "string"
+			info.Size = int64(len(e.value))
+			info.Preview = cache.Preview(e.value)
+			if !e.expiresAt.IsZero() {
+				info.TTL = e.expiresAt.Sub(now)
+			}
+		} else if z := c.zset(key, false); z != nil {
+			info.Exists, info.Type = true, "zset"
+			info.Size = int64(len(z.scores))
+			if !z.expiresAt.IsZero() {
+				info.TTL = z.expiresAt.Sub(now)
+			}
+		} else if set, ok := c.sets[key]; ok {
+			info.Exists, info.Type = true, "set"
+			info.Size = int64(len(set))
+		}
+
+		infos[i] = info
+	}
+
+	return infos, nil
+}
+
+// DeletePrefix Удалить ключи с префиксом
+func (c *Cache) DeletePrefix(_ context.Context, prefix string) (int, error) {
+	const op = "cache.memoryCache.DeletePrefix"
+
+	if prefix == "" {
+		return 0, fmt.Errorf("%s: %w", op, cache.ErrEmptyPrefix)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var deleted int
+	for key, el := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(el)
+			deleted++
+		}
+	}
+	for key := range c.zsets {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.zsets, key)
+			deleted++
+		}
+	}
+	for key := range c.sets {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.sets, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
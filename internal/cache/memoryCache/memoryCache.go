@@ -0,0 +1,143 @@
+//internal/cache/memoryCache/memoryCache.go
+
+// LRU-кэш в памяти процесса. Используется, когда Redis недоступен или не нужен (локальный запуск, тесты)
+package memoryCache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"test-redis/internal/cache"
+	"test-redis/internal/lib/metrics"
+	"time"
+)
+
+// backend Значение метки backend в метриках
+const backend = "memory"
+
+// entry Элемент кэша
+type entry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // нулевое значение - без ограничения срока жизни
+}
+
+// Cache Структура объекта Cache
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List // в начале списка - недавно использованные элементы
+	ttl      cache.TTLPolicy
+	zsets    map[string]*zset // упорядоченные множества (рейтинги)
+	sets     map[string]map[string]struct{}
+	hits     map[string][]time.Time // время запросов для ограничения частоты (ratelimit)
+}
+
+// NewCache Конструктор объекта Cache. capacity - максимальное количество ключей, при превышении вытесняются давно не использованные
+func NewCache(capacity int, ttl cache.TTLPolicy) *Cache {
+	if capacity <= 0 {
+		capacity = 1
+	}
+
+	return &Cache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element, capacity),
+		order:    list.New(),
+		ttl:      ttl,
+		zsets:    make(map[string]*zset),
+		sets:     make(map[string]map[string]struct{}),
+		hits:     make(map[string][]time.Time),
+	}
+}
+
+// SetTTLPolicy Замена правил вычисления времени жизни записей
+func (c *Cache) SetTTLPolicy(policy cache.TTLPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ttl = policy
+}
+
+// Get Получение значения из кеша
+func (c *Cache) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		metrics.CacheOperationsTotal.Inc(backend, "get", metrics.CacheMiss)
+		return nil, cache.ErrDataNotFound
+	}
+
+	e := el.Value.(*entry)
+	if e.expired(time.Now()) {
+		c.remove(el)
+		metrics.CacheOperationsTotal.Inc(backend, "get", metrics.CacheMiss)
+		return nil, cache.ErrDataNotFound
+	}
+
+	c.order.MoveToFront(el)
+	metrics.CacheOperationsTotal.Inc(backend, "get", metrics.CacheHit)
+
+	return e.value, nil
+}
+
+// Set Запись значения в кеш
+func (c *Cache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl = c.ttl.TTL(key, ttl); ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		e.value = value
+		e.expiresAt = expiresAt
+		c.order.MoveToFront(el)
+		return nil
+	}
+
+	c.items[key] = c.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+
+	// Вытесняем давно не использованные элементы
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+
+	return nil
+}
+
+// Delete Удаление ключей из кеша
+func (c *Cache) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := c.items[key]; ok {
+			c.remove(el)
+		}
+		delete(c.zsets, key)
+		delete(c.sets, key)
+	}
+
+	return nil
+}
+
+// Ping Кэш в памяти доступен всегда
+func (c *Cache) Ping(_ context.Context) error {
+	return nil
+}
+
+// remove Удаляет элемент из списка и индекса. Вызывается под блокировкой
+func (c *Cache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*entry).key)
+}
+
+func (e *entry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
@@ -0,0 +1,53 @@
+//internal/cache/memoryCache/ratelimit.go
+
+package memoryCache
+
+import (
+	"context"
+	"test-redis/internal/cache"
+	"time"
+)
+
+// Allow Проверка ограничения частоты запросов (скользящее окно). Действует только в пределах одного экземпляра сервиса
+func (c *Cache) Allow(_ context.Context, key string, limit int, window time.Duration) (cache.RateLimit, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+
+	// Отбрасываем запросы, вышедшие за окно (время запросов - по возрастанию)
+	hits := c.hits[key]
+	i := 0
+	for i < len(hits) && !hits[i].After(now.Add(-window)) {
+		i++
+	}
+	hits = hits[i:]
+
+	res := cache.RateLimit{Limit: limit}
+	if len(hits) < limit {
+		hits = append(hits, now)
+		res.Allowed = true
+		res.Remaining = limit - len(hits)
+		res.ResetAfter = hits[0].Add(window).Sub(now)
+	} else {
+		res.RetryAfter = hits[0].Add(window).Sub(now)
+		res.ResetAfter = res.RetryAfter
+	}
+
+	if len(hits) == 0 {
+		delete(c.hits, key)
+	} else {
+		c.hits[key] = hits
+	}
+
+	// Ключи клиентов, которые больше не приходят, сами не удаляются - чистим, когда их становится слишком много
+	if len(c.hits) > c.capacity {
+		for k, h := range c.hits {
+			if !h[len(h)-1].After(now.Add(-window)) {
+				delete(c.hits, k)
+			}
+		}
+	}
+
+	return res, nil
+}
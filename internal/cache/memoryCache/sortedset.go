@@ -0,0 +1,123 @@
+//internal/cache/memoryCache/sortedset.go
+
+package memoryCache
+
+import (
+	"context"
+	"sort"
+	"test-redis/internal/cache"
+	"time"
+)
+
+// zset Упорядоченное множество. В ограничение capacity не входит
+type zset struct {
+	scores    map[string]float64
+	expiresAt time.Time // нулевое значение - без ограничения срока жизни
+}
+
+// ZAdd Установить оценки элементов
+func (c *Cache) ZAdd(_ context.Context, key string, members ...cache.Member) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	z := c.zset(key, true)
+	for _, m := range members {
+		z.scores[m.Name] = m.Score
+	}
+
+	return nil
+}
+
+// ZIncrBy Увеличить оценку элемента и, если задан ttl, продлить время жизни множества
+func (c *Cache) ZIncrBy(_ context.Context, key string, member string, incr float64, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	z := c.zset(key, true)
+	z.scores[member] += incr
+	if ttl > 0 {
+		z.expiresAt = time.Now().Add(ttl)
+	}
+
+	return nil
+}
+
+// ZRem Удалить элементы
+func (c *Cache) ZRem(_ context.Context, key string, members ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	z := c.zset(key, false)
+	if z == nil {
+		return nil
+	}
+	for _, m := range members {
+		delete(z.scores, m)
+	}
+	if len(z.scores) == 0 {
+		delete(c.zsets, key)
+	}
+
+	return nil
+}
+
+// ZTop Первые n элементов по убыванию оценки
+func (c *Cache) ZTop(ctx context.Context, key string, n int) ([]cache.Member, error) {
+	return c.ZUnion(ctx, []string{key}, nil, n)
+}
+
+// ZUnion Объединение множеств с весами
+func (c *Cache) ZUnion(_ context.Context, keys []string, weights []float64, n int) ([]cache.Member, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	scores := make(map[string]float64)
+	for i, key := range keys {
+		z := c.zset(key, false)
+		if z == nil {
+			continue
+		}
+		weight := 1.0
+		if i < len(weights) {
+			weight = weights[i]
+		}
+		for name, score := range z.scores {
+			scores[name] += score * weight
+		}
+	}
+
+	res := make([]cache.Member, 0, len(scores))
+	for name, score := range scores {
+		res = append(res, cache.Member{Name: name, Score: score})
+	}
+	// Как в Redis: при равных оценках - в обратном лексикографическом порядке
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].Name > res[j].Name
+	})
+	if n > 0 && len(res) > n {
+		res = res[:n]
+	}
+
+	return res, nil
+}
+
+// zset Возвращает множество по ключу (истекшие удаляются). create - создать, если его нет. Вызывается под блокировкой
+func (c *Cache) zset(key string, create bool) *zset {
+	z, ok := c.zsets[key]
+	if ok && !z.expiresAt.IsZero() && time.Now().After(z.expiresAt) {
+		delete(c.zsets, key)
+		ok = false
+	}
+	if !ok {
+		if !create {
+			return nil
+		}
+		z = &zset{scores: make(map[string]float64)}
+		c.zsets[key] = z
+	}
+
+	return z
+}
@@ -0,0 +1,63 @@
+//internal/cache/noopCache/noopCache.go
+
+// Кэш-заглушка: ничего не хранит. Используется, когда кэширование отключено
+package noopCache
+
+import (
+	"context"
+	"test-redis/internal/cache"
+	"time"
+)
+
+// Cache Структура объекта Cache
+type Cache struct{}
+
+// NewCache Конструктор объекта Cache
+func NewCache() *Cache {
+	return &Cache{}
+}
+
+// Get Всегда возвращает cache.ErrDataNotFound
+func (c *Cache) Get(_ context.Context, _ string) ([]byte, error) {
+	return nil, cache.ErrDataNotFound
+}
+
+// Set Ничего не делает
+func (c *Cache) Set(_ context.Context, _ string, _ []byte, _ time.Duration) error {
+	return nil
+}
+
+// Delete Ничего не делает
+func (c *Cache) Delete(_ context.Context, _ ...string) error {
+	return nil
+}
+
+// Ping Ничего не делает
+func (c *Cache) Ping(_ context.Context) error {
+	return nil
+}
+
+// ZAdd Ничего не делает
+func (c *Cache) ZAdd(_ context.Context, _ string, _ ...cache.Member) error {
+	return nil
+}
+
+// ZIncrBy Ничего не делает
+func (c *Cache) ZIncrBy(_ context.Context, _ string, _ string, _ float64, _ time.Duration) error {
+	return nil
+}
+
+// ZRem Ничего не делает
+func (c *Cache) ZRem(_ context.Context, _ string, _ ...string) error {
+	return nil
+}
+
+// ZTop Всегда возвращает пустой список
+func (c *Cache) ZTop(_ context.Context, _ string, _ int) ([]cache.Member, error) {
+	return nil, nil
+}
+
+// ZUnion Всегда возвращает пустой список
+func (c *Cache) ZUnion(_ context.Context, _ []string, _ []float64, _ int) ([]cache.Member, error) {
+	return nil, nil
+}
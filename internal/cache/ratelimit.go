@@ -0,0 +1,22 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimit Результат проверки ограничения частоты запросов
+type RateLimit struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int           // Сколько запросов еще можно сделать в текущем окне
+	RetryAfter time.Duration // Через сколько можно повторить запрос (только если Allowed == false)
+	ResetAfter time.Duration // Через сколько окно полностью освободится
+}
+
+// RateLimiter Необязательный интерфейс кэша: ограничение частоты запросов по скользящему окну.
+// При хранении в Redis ограничение действует сразу для всех экземпляров сервиса
+type RateLimiter interface {
+	// Allow Учесть запрос с ключом key, если за последние window их было меньше limit
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimit, error)
+}
@@ -0,0 +1,80 @@
+//internal/cache/redisCache/counters.go
+
+package redisCache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"test-redis/internal/lib/metrics"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// IncrBy Увеличить счетчик (INCRBY)
+func (c *Cache) IncrBy(ctx context.Context, key string, n int64) (int64, error) {
+	const op = "cache.redisCache.IncrBy"
+
+	value, err := c.client.IncrBy(ctx, c.prefix+key, n).Result()
+	if err != nil {
+		metrics.CacheOperationsTotal.Inc(backend, "incrby", metrics.CacheError)
+		return 0, fmt.Errorf("%s: incrby key %s: %w", op, key, err)
+	}
+	metrics.CacheOperationsTotal.Inc(backend, "incrby", metrics.CacheOK)
+
+	return value, nil
+}
+
+// GetDel Прочитать и удалить счетчик (GETDEL, Redis 6.2+)
+func (c *Cache) GetDel(ctx context.Context, key string) (int64, error) {
+	const op = "cache.redisCache.GetDel"
+
+	value, err := c.client.GetDel(ctx, c.prefix+key).Int64()
+	if errors.Is(err, redis.Nil) {
+		metrics.CacheOperationsTotal.Inc(backend, "getdel", metrics.CacheMiss)
+		return 0, nil
+	}
+	if err != nil {
+		metrics.CacheOperationsTotal.Inc(backend, "getdel", metrics.CacheError)
+		return 0, fmt.Errorf("%s: getdel key %s: %w", op, key, err)
+	}
+	metrics.CacheOperationsTotal.Inc(backend, "getdel", metrics.CacheHit)
+
+	return value, nil
+}
+
+// SAdd Добавить элементы в множество (SADD)
+func (c *Cache) SAdd(ctx context.Context, key string, members ...string) error {
+	const op = "cache.redisCache.SAdd"
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	args := make([]any, len(members))
+	for i, m := range members {
+		args[i] = m
+	}
+
+	if err := c.client.SAdd(ctx, c.prefix+key, args...).Err(); err != nil {
+		metrics.CacheOperationsTotal.Inc(backend, "sadd", metrics.CacheError)
+		return fmt.Errorf("%s: sadd key %s: %w", op, key, err)
+	}
+	metrics.CacheOperationsTotal.Inc(backend, "sadd", metrics.CacheOK)
+
+	return nil
+}
+
+// SPop Извлечь до n случайных элементов множества (SPOP key count)
+func (c *Cache) SPop(ctx context.Context, key string, n int) ([]string, error) {
+	const op = "cache.redisCache.SPop"
+
+	members, err := c.client.SPopN(ctx, c.prefix+key, int64(n)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		metrics.CacheOperationsTotal.Inc(backend, "spop", metrics.CacheError)
+		return nil, fmt.Errorf("%s: spop key %s: %w", op, key, err)
+	}
+	metrics.CacheOperationsTotal.Inc(backend, "spop", metrics.CacheOK)
+
+	return members, nil
+}
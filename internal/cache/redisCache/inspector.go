@@ -0,0 +1,186 @@
+//internal/cache/redisCache/inspector.go
+
+package redisCache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"test-redis/internal/cache"
+	"test-redis/internal/lib/metrics"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// scanCount Подсказка Redis, сколько ключей просматривать за один вызов SCAN
+const scanCount = 1000
+
+// globEscaper Экранирование спецсимволов шаблона MATCH
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// Scan Ключи с префиксом (SCAN MATCH, в режиме cluster - на каждом master-сервере)
+func (c *Cache) Scan(ctx context.Context, prefix string, limit int) ([]string, error) {
+	const op = "cache.redisCache.Scan"
+
+	var (
+		mu   sync.Mutex
+		keys []string
+	)
+	err := c.forEachNode(ctx, func(ctx context.Context, client redis.UniversalClient) error {
+		iter := client.Scan(ctx, 0, globEscaper.Replace(c.prefix+prefix)+"*", scanCount).Iterator()
+		for iter.Next(ctx) {
+			mu.Lock()
+			if limit > 0 && len(keys) >= limit {
+				mu.Unlock()
+				return nil
+			}
+			keys = append(keys, strings.TrimPrefix(iter.Val(), c.prefix))
+			mu.Unlock()
+		}
+		return iter.Err()
+	})
+	if err != nil {
+		metrics.CacheOperationsTotal.Inc(backend, "scan", metrics.CacheError)
+		return nil, fmt.Errorf("%s: prefix %s: %w", op, prefix, err)
+	}
+	metrics.CacheOperationsTotal.Inc(backend, "scan", metrics.CacheOK)
+
+	return keys, nil
+}
+
+// Inspect Сведения о ключах (TYPE, PTTL, затем размер и начало значения - в зависимости от типа)
+func (c *Cache) Inspect(ctx context.Context, keys ...string) ([]cache.KeyInfo, error) {
+	const op = "cache.redisCache.Inspect"
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	types := make([]*redis.StatusCmd, len(keys))
+	ttls := make([]*redis.DurationCmd, len(keys))
+	pipe := c.client.Pipeline()
+	for i, key := range keys {
+		types[i] = pipe.Type(ctx, c.prefix+key)
+		ttls[i] = pipe.PTTL(ctx, c.prefix+key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		metrics.CacheOperationsTotal.Inc(backend, "inspect", metrics.CacheError)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	infos := make([]cache.KeyInfo, len(keys))
+	sizes := make([]*redis.IntCmd, len(keys))
+	values := make([]*redis.StringCmd, len(keys))
+	pipe = c.client.Pipeline()
+	for i, key := range keys {
+		infos[i] = cache.KeyInfo{Key: key, Type: types[i].Val()}
+		if infos[i].Type == "none" {
+			infos[i].Type = ""
+			continue
+		}
+		infos[i].Exists = true
+		// PTTL возвращает -1, если время жизни не ограничено
+		if ttl := ttls[i].Val(); ttl > 0 {
+			infos[i].TTL = ttl
+		}
+
+		switch infos[i].Type {
+		case "string":
+			sizes[i] = pipe.StrLen(ctx, c.prefix+key)
+			values[i] = pipe.GetRange(ctx, c.prefix+key, 0, cache.PreviewSize) // на байт больше, чтобы Preview понял, что значение длиннее
+		case "zset":
+			sizes[i] = pipe.ZCard(ctx, c.prefix+key)
+		case "set":
+			sizes[i] = pipe.SCard(ctx, c.prefix+key)
+		}
+	}
+	if pipe.Len() > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			metrics.CacheOperationsTotal.Inc(backend, "inspect", metrics.CacheError)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	metrics.CacheOperationsTotal.Inc(backend, "inspect", metrics.CacheOK)
+
+	for i := range infos {
+		if sizes[i] != nil {
+			infos[i].Size = sizes[i].Val()
+		}
+		if values[i] != nil {
+			infos[i].Preview = cache.Preview([]byte(values[i].Val()))
+		}
+	}
+
+	return infos, nil
+}
+
+// DeletePrefix Удалить ключи с префиксом (SCAN + DEL по одному ключу в конвейере, в режиме cluster - на каждом master-сервере)
+func (c *Cache) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	const op = "cache.redisCache.DeletePrefix"
+
+	if prefix == "" {
+		return 0, fmt.Errorf("%s: %w", op, cache.ErrEmptyPrefix)
+	}
+
+	var (
+		mu      sync.Mutex
+		deleted int
+	)
+	err := c.forEachNode(ctx, func(ctx context.Context, client redis.UniversalClient) error {
+		iter := client.Scan(ctx, 0, globEscaper.Replace(c.prefix+prefix)+"*", scanCount).Iterator()
+		batch := make([]string, 0, scanCount)
+
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			pipe := client.Pipeline()
+			cmds := make([]*redis.IntCmd, len(batch))
+			for i, key := range batch {
+				cmds[i] = pipe.Del(ctx, key)
+			}
+			if _, err := pipe.Exec(ctx); err != nil {
+				return err
+			}
+			mu.Lock()
+			for _, cmd := range cmds {
+				deleted += int(cmd.Val())
+			}
+			mu.Unlock()
+			batch = batch[:0]
+			return nil
+		}
+
+		for iter.Next(ctx) {
+			batch = append(batch, iter.Val())
+			if len(batch) == scanCount {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return err
+		}
+		return flush()
+	})
+	if err != nil {
+		metrics.CacheOperationsTotal.Inc(backend, "delete", metrics.CacheError)
+		return deleted, fmt.Errorf("%s: prefix %s: %w", op, prefix, err)
+	}
+	metrics.CacheOperationsTotal.Inc(backend, "delete", metrics.CacheOK)
+
+	return deleted, nil
+}
+
+// forEachNode Выполнить fn для каждого сервера с данными: в режиме cluster - для каждого master-сервера, иначе - один раз
+func (c *Cache) forEachNode(ctx context.Context, fn func(ctx context.Context, client redis.UniversalClient) error) error {
+	if cluster, ok := c.client.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return fn(ctx, client)
+		})
+	}
+
+	return fn(ctx, c.client)
+}
@@ -0,0 +1,71 @@
+//internal/cache/redisCache/ratelimit.go
+
+package redisCache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"test-redis/internal/cache"
+	"test-redis/internal/lib/metrics"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript Скользящее окно на упорядоченном множестве: элемент - запрос, оценка - его время (мс).
+// Проверка и запись выполняются атомарно; время берется у Redis, чтобы не зависеть от часов экземпляров сервиса.
+// Возвращает {разрешен, осталось, повторить через (мс), окно освободится через (мс)}
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+
+if count < limit then
+	redis.call('ZADD', key, now, now .. '-' .. ARGV[3])
+	redis.call('PEXPIRE', key, window)
+	local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+	return {1, limit - count - 1, 0, tonumber(oldest[2]) + window - now}
+end
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+local retry = tonumber(oldest[2]) + window - now
+return {0, 0, retry, retry}
+`)
+
+// Allow Проверка ограничения частоты запросов (скрипт Lua со скользящим окном)
+func (c *Cache) Allow(ctx context.Context, key string, limit int, window time.Duration) (cache.RateLimit, error) {
+	const op = "cache.redisCache.Allow"
+
+	// Уникальный суффикс элемента: запросы в одну миллисекунду не должны схлопываться
+	var nonce [8]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return cache.RateLimit{}, fmt.Errorf("%s: nonce: %w", op, err)
+	}
+
+	res, err := slidingWindowScript.Run(ctx, c.client, []string{c.prefix + key},
+		window.Milliseconds(), limit, hex.EncodeToString(nonce[:])).Int64Slice()
+	if err != nil {
+		metrics.CacheOperationsTotal.Inc(backend, "ratelimit", metrics.CacheError)
+		return cache.RateLimit{}, fmt.Errorf("%s: key %s: %w", op, key, err)
+	}
+	metrics.CacheOperationsTotal.Inc(backend, "ratelimit", metrics.CacheOK)
+
+	if len(res) != 4 {
+		return cache.RateLimit{}, fmt.Errorf("%s: unexpected script result %v", op, res)
+	}
+
+	return cache.RateLimit{
+		Allowed:    res[0] == 1,
+		Limit:      limit,
+		Remaining:  int(res[1]),
+		RetryAfter: time.Duration(res[2]) * time.Millisecond,
+		ResetAfter: time.Duration(res[3]) * time.Millisecond,
+	}, nil
+}
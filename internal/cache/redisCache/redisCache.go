@@ -4,83 +4,251 @@ package redisCache
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"github.com/redis/go-redis/v9"
-	"test-redis/internal/models"
+	"os"
+	"sync/atomic"
+	"test-redis/internal/cache"
+	"test-redis/internal/lib/metrics"
 	"time"
 )
 
-// ctx Текущий контекст
-var ctx = context.Background()
+// Режимы подключения к Redis (cache.mode в конфигурации)
+const (
+	ModeSingle   = "single"   // Один сервер
+	ModeSentinel = "sentinel" // Master/replica с автоматическим переключением через Sentinel
+	ModeCluster  = "cluster"  // Redis Cluster
+)
+
+// backend Значение метки backend в метриках
+const backend = "redis"
 
 // Cache Структура объекта Cache
 type Cache struct {
-	client *redis.Client
+	client redis.UniversalClient           // Общий интерфейс для одиночного сервера, Sentinel и Cluster
+	prefix string                          // Пространство имен ключей, чтобы несколько окружений могли использовать один Redis
+	ttl    atomic.Pointer[cache.TTLPolicy] // Правила вычисления времени жизни записей (могут меняться на ходу)
+}
+
+// Options Параметры подключения и работы кэша
+type Options struct {
+	Mode             string   // single (по умолчанию), sentinel или cluster
+	Address          string   // Адрес сервера (single); используется, если не заданы Addresses
+	Addresses        []string // Адреса Sentinel-серверов (sentinel) или узлов кластера (cluster)
+	MasterName       string   // Имя master-сервера (sentinel)
+	Username         string
+	Password         string
+	SentinelPassword string
+	DB               int // В режиме cluster не поддерживается
+	PoolSize         int // 0 - значение go-redis по умолчанию (10 на CPU)
+	MinIdleConns     int
+	TLS              TLSOptions
+	Prefix           string // Добавляется ко всем ключам, например "dev:"
+	TTL              cache.TTLPolicy
+	Timeout          time.Duration // Ограничение времени одной команды (чтение и запись), 0 - значение go-redis по умолчанию (3s)
+}
+
+// TLSOptions Параметры TLS-подключения
+type TLSOptions struct {
+	Enabled            bool
+	CAFile             string // Сертификат CA для проверки сервера (если пусто - системные)
+	CertFile           string // Клиентский сертификат (mTLS)
+	KeyFile            string
+	ServerName         string
+	InsecureSkipVerify bool
 }
 
 // NewCache Конструктор объекта Cache
-func NewCache(address string, password string, db int) (*Cache, error) {
+func NewCache(opts Options) (*Cache, error) {
 	const op = "cache.redisCache.NewCache" // Имя текущей функции для логов и ошибок
 
+	tlsConfig, err := opts.TLS.config()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	addresses := opts.Addresses
+	if len(addresses) == 0 && opts.Address != "" {
+		addresses = []string{opts.Address}
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("%s: no redis address specified", op)
+	}
+
 	// Подключаемся к redis
-	client := redis.NewClient(&redis.Options{
-		Addr:     address,  // "localhost:6379",
-		Password: password, // ""
-		DB:       db,       // 0
-	})
+	var client redis.UniversalClient
+	switch opts.Mode {
+	case ModeSingle, "":
+		client = redis.NewClient(&redis.Options{
+			Addr:         addresses[0], // "localhost:6379",
+			Username:     opts.Username,
+			Password:     opts.Password, // ""
+			DB:           opts.DB,       // 0
+			PoolSize:     opts.PoolSize,
+			MinIdleConns: opts.MinIdleConns,
+			TLSConfig:    tlsConfig,
+			ReadTimeout:  opts.Timeout,
+			WriteTimeout: opts.Timeout,
+			// Учитывать срок и отмену ctx команды (запрос клиента, остановка сервера), а не только ReadTimeout/WriteTimeout
+			ContextTimeoutEnabled: true,
+		})
+	case ModeSentinel:
+		if opts.MasterName == "" {
+			return nil, fmt.Errorf("%s: master name is required in sentinel mode", op)
+		}
+		client = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       opts.MasterName,
+			SentinelAddrs:    addresses,
+			SentinelPassword: opts.SentinelPassword,
+			Username:         opts.Username,
+			Password:         opts.Password,
+			DB:               opts.DB,
+			PoolSize:         opts.PoolSize,
+			MinIdleConns:     opts.MinIdleConns,
+			TLSConfig:        tlsConfig,
+			ReadTimeout:      opts.Timeout,
+			WriteTimeout:     opts.Timeout,
+
+			ContextTimeoutEnabled: true,
+		})
+	case ModeCluster:
+		client = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        addresses,
+			Username:     opts.Username,
+			Password:     opts.Password,
+			PoolSize:     opts.PoolSize,
+			MinIdleConns: opts.MinIdleConns,
+			TLSConfig:    tlsConfig,
+			ReadTimeout:  opts.Timeout,
+			WriteTimeout: opts.Timeout,
+
+			ContextTimeoutEnabled: true,
+		})
+	default:
+		return nil, fmt.Errorf("%s: unknown redis mode %q", op, opts.Mode)
+	}
+
+	c := &Cache{client: client, prefix: opts.Prefix}
+	c.ttl.Store(&opts.TTL)
+
+	return c, nil
+}
+
+// config Собирает *tls.Config. Если TLS выключен - nil
+func (o TLSOptions) config() (*tls.Config, error) {
+	if !o.Enabled {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         o.ServerName,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+
+	if o.CAFile != "" {
+		caCert, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", o.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
 
-	return &Cache{client: client}, nil
+	if o.CertFile != "" || o.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
 }
 
-// SetKey Установка ключа
-func (c *Cache) SetKey(id string, value any, expiration time.Duration) error {
-	err := c.client.Set(ctx, "article:"+id, value, expiration).Err()
+// Get Получение значения из кеша Redis
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	const op = "cache.redisCache.Get"
+
+	raw, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		metrics.CacheOperationsTotal.Inc(backend, "get", metrics.CacheMiss)
+		return nil, cache.ErrDataNotFound
+	}
 	if err != nil {
-		return err
+		metrics.CacheOperationsTotal.Inc(backend, "get", metrics.CacheError)
+		return nil, fmt.Errorf("%s: get key %s: %w", op, key, err)
+	}
+	metrics.CacheOperationsTotal.Inc(backend, "get", metrics.CacheHit)
+
+	return raw, nil
+}
+
+// Set Запись значения в кеш Redis
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	const op = "cache.redisCache.Set"
+
+	if err := c.client.Set(ctx, c.prefix+key, value, c.ttl.Load().TTL(key, ttl)).Err(); err != nil {
+		metrics.CacheOperationsTotal.Inc(backend, "set", metrics.CacheError)
+		return fmt.Errorf("%s: set key %s: %w", op, key, err)
 	}
+	metrics.CacheOperationsTotal.Inc(backend, "set", metrics.CacheOK)
+
 	return nil
 }
 
-// SetCachedArticle Получение данных о статье из кеша Redis
-func (c *Cache) SetCachedArticle(id string, value any) error {
+// Delete Удаление ключей из кеша Redis
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	const op = "cache.redisCache.Delete"
+
+	if len(keys) == 0 {
+		return nil
+	}
 
-	err := c.client.Set(ctx, "article:"+id, value, time.Second*100).Err()
+	// Ключи удаляем по одному в конвейере: в режиме cluster DEL с ключами из разных слотов вернет CROSSSLOT
+	pipe := c.client.Pipeline()
+	for _, key := range keys {
+		pipe.Del(ctx, c.prefix+key)
+	}
 
-	if err != nil {
-		fmt.Println("error setting value ", err)
-		return err
+	if _, err := pipe.Exec(ctx); err != nil {
+		metrics.CacheOperationsTotal.Inc(backend, "delete", metrics.CacheError)
+		return fmt.Errorf("%s: delete keys %v: %w", op, keys, err)
 	}
+	metrics.CacheOperationsTotal.Inc(backend, "delete", metrics.CacheOK)
 
 	return nil
 }
 
-// GetCachedArticle Получение данных о статье из кеша Redis
-func (c *Cache) GetCachedArticle(id string) ([]models.ArticleInfo, error) {
-	raw, err := c.client.Get(ctx, "article:"+id).Result()
-	fmt.Println(raw)
-	if err == redis.Nil {
-		return nil, fmt.Errorf("key %s does not exist", "article:"+id)
-	} else if err != nil {
-		return nil, err
-	}
-	var info []models.ArticleInfo
-	err = json.Unmarshal([]byte(raw), &info)
-	fmt.Println("got from cache:")
-	fmt.Println(info)
-	if err != nil {
-		return nil, fmt.Errorf("can't unmarshal raw value %s", "article:"+id)
+// Ping Проверка доступности Redis
+func (c *Cache) Ping(ctx context.Context) error {
+	const op = "cache.redisCache.Ping"
+
+	if err := c.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	return info, nil
+	return nil
+}
+
+// SetTTLPolicy Замена правил вычисления времени жизни записей
+func (c *Cache) SetTTLPolicy(policy cache.TTLPolicy) {
+	c.ttl.Store(&policy)
 }
 
-func (c *Cache) GetCachedArticleAsString(key string) (string, error) {
-	raw, err := c.client.Get(ctx, "article:"+key).Result()
-	if err == redis.Nil {
-		return "", fmt.Errorf("key %s does not exist", "article:"+key)
-	} else if err != nil {
-		return "", err
+// Close Закрывает соединения с Redis. После закрытия кэш использовать нельзя
+func (c *Cache) Close() error {
+	const op = "cache.redisCache.Close"
+
+	if err := c.client.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
-	return raw, nil
+
+	return nil
 }
@@ -0,0 +1,134 @@
+//internal/cache/redisCache/sortedset.go
+
+package redisCache
+
+import (
+	"context"
+	"fmt"
+	"test-redis/internal/cache"
+	"test-redis/internal/lib/metrics"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ZAdd Установить оценки элементов (ZADD)
+func (c *Cache) ZAdd(ctx context.Context, key string, members ...cache.Member) error {
+	const op = "cache.redisCache.ZAdd"
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	z := make([]redis.Z, len(members))
+	for i, m := range members {
+		z[i] = redis.Z{Score: m.Score, Member: m.Name}
+	}
+
+	if err := c.client.ZAdd(ctx, c.prefix+key, z...).Err(); err != nil {
+		metrics.CacheOperationsTotal.Inc(backend, "zadd", metrics.CacheError)
+		return fmt.Errorf("%s: zadd key %s: %w", op, key, err)
+	}
+	metrics.CacheOperationsTotal.Inc(backend, "zadd", metrics.CacheOK)
+
+	return nil
+}
+
+// ZIncrBy Увеличить оценку элемента (ZINCRBY) и, если задан ttl, продлить время жизни множества (EXPIRE)
+func (c *Cache) ZIncrBy(ctx context.Context, key string, member string, incr float64, ttl time.Duration) error {
+	const op = "cache.redisCache.ZIncrBy"
+
+	pipe := c.client.Pipeline()
+	pipe.ZIncrBy(ctx, c.prefix+key, incr, member)
+	if ttl > 0 {
+		pipe.Expire(ctx, c.prefix+key, ttl)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		metrics.CacheOperationsTotal.Inc(backend, "zincrby", metrics.CacheError)
+		return fmt.Errorf("%s: zincrby key %s: %w", op, key, err)
+	}
+	metrics.CacheOperationsTotal.Inc(backend, "zincrby", metrics.CacheOK)
+
+	return nil
+}
+
+// ZRem Удалить элементы (ZREM)
+func (c *Cache) ZRem(ctx context.Context, key string, members ...string) error {
+	const op = "cache.redisCache.ZRem"
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	args := make([]any, len(members))
+	for i, m := range members {
+		args[i] = m
+	}
+
+	if err := c.client.ZRem(ctx, c.prefix+key, args...).Err(); err != nil {
+		metrics.CacheOperationsTotal.Inc(backend, "zrem", metrics.CacheError)
+		return fmt.Errorf("%s: zrem key %s: %w", op, key, err)
+	}
+	metrics.CacheOperationsTotal.Inc(backend, "zrem", metrics.CacheOK)
+
+	return nil
+}
+
+// ZTop Первые n элементов по убыванию оценки (ZREVRANGE ... WITHSCORES)
+func (c *Cache) ZTop(ctx context.Context, key string, n int) ([]cache.Member, error) {
+	const op = "cache.redisCache.ZTop"
+
+	z, err := c.client.ZRevRangeWithScores(ctx, c.prefix+key, 0, int64(n)-1).Result()
+	if err != nil {
+		metrics.CacheOperationsTotal.Inc(backend, "zrange", metrics.CacheError)
+		return nil, fmt.Errorf("%s: zrevrange key %s: %w", op, key, err)
+	}
+	metrics.CacheOperationsTotal.Inc(backend, "zrange", metrics.CacheOK)
+
+	return members(z), nil
+}
+
+// ZUnion Объединение множеств с весами (ZUNION ... WEIGHTS ... WITHSCORES).
+// Redis не ограничивает размер результата ZUNION, поэтому первые n элементов отбираются на стороне приложения
+func (c *Cache) ZUnion(ctx context.Context, keys []string, weights []float64, n int) ([]cache.Member, error) {
+	const op = "cache.redisCache.ZUnion"
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+
+	z, err := c.client.ZUnionWithScores(ctx, redis.ZStore{Keys: prefixed, Weights: weights, Aggregate: "SUM"}).Result()
+	if err != nil {
+		metrics.CacheOperationsTotal.Inc(backend, "zunion", metrics.CacheError)
+		return nil, fmt.Errorf("%s: zunion keys %v: %w", op, keys, err)
+	}
+	metrics.CacheOperationsTotal.Inc(backend, "zunion", metrics.CacheOK)
+
+	// ZUNION возвращает элементы по возрастанию оценки
+	res := members(z)
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	if n > 0 && len(res) > n {
+		res = res[:n]
+	}
+
+	return res, nil
+}
+
+// members Преобразование результата go-redis
+func members(z []redis.Z) []cache.Member {
+	res := make([]cache.Member, 0, len(z))
+	for _, item := range z {
+		name, _ := item.Member.(string)
+		res = append(res, cache.Member{Name: name, Score: item.Score})
+	}
+
+	return res
+}
@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Member Элемент упорядоченного множества
+type Member struct {
+	Name  string
+	Score float64
+}
+
+// SortedSets Необязательный интерфейс кэша: упорядоченные множества (Redis ZSET), на которых строятся рейтинги.
+// Ключи, участвующие в одном ZUnion, в режиме cluster должны попадать в один слот (общий hash tag, например "{lb}:...")
+type SortedSets interface {
+	// ZAdd Установить оценки элементов
+	ZAdd(ctx context.Context, key string, members ...Member) error
+	// ZIncrBy Увеличить оценку элемента. ttl > 0 - заодно продлить время жизни множества
+	ZIncrBy(ctx context.Context, key string, member string, incr float64, ttl time.Duration) error
+	// ZRem Удалить элементы. Отсутствие элемента ошибкой не считается
+	ZRem(ctx context.Context, key string, members ...string) error
+	// ZTop Первые n элементов по убыванию оценки (n <= 0 - все)
+	ZTop(ctx context.Context, key string, n int) ([]Member, error)
+	// ZUnion Объединение множеств: оценка элемента - сумма его оценок, умноженных на веса множеств.
+	// Результат упорядочен по убыванию оценки, n <= 0 - все элементы
+	ZUnion(ctx context.Context, keys []string, weights []float64, n int) ([]Member, error)
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// TTLPolicy Правила вычисления времени жизни записей в кэше
+type TTLPolicy struct {
+	Default   time.Duration            // Время жизни по умолчанию (0 - без ограничения)
+	Overrides map[string]time.Duration // Время жизни для отдельных сущностей. Сущность - часть ключа до первого ':' (например, "article")
+	Jitter    float64                  // Доля случайного разброса времени жизни (0.1 - ±10%), чтобы записи не истекали одновременно
+}
+
+// TTL Время жизни записи с ключом key. Явно заданное ttl > 0 используется как есть (вызывающая сторона
+// знает точный срок, например срок действия токена), иначе берется значение для сущности, иначе значение по умолчанию,
+// и к нему добавляется случайный разброс
+func (p TTLPolicy) TTL(key string, ttl time.Duration) time.Duration {
+	if ttl > 0 {
+		return ttl
+	}
+
+	ttl = p.Default
+	if override, ok := p.Overrides[Entity(key)]; ok {
+		ttl = override
+	}
+
+	if ttl <= 0 || p.Jitter <= 0 {
+		return ttl
+	}
+
+	// Равномерно распределенное отклонение в диапазоне [-Jitter*ttl, +Jitter*ttl]
+	delta := time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(ttl))
+	if ttl+delta <= 0 {
+		return ttl
+	}
+
+	return ttl + delta
+}
+
+// Entity Сущность, к которой относится ключ: часть ключа до первого ':'
+func Entity(key string) string {
+	entity, _, _ := strings.Cut(key, ":")
+	return entity
+}
+
+// TTLUpdater Необязательный интерфейс кэша: замена правил вычисления времени жизни без перезапуска
+// (перечитывание конфигурации). Новые правила действуют для записей, сохраненных после замены
+type TTLUpdater interface {
+	SetTTLPolicy(policy TTLPolicy)
+}
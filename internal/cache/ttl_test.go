@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLPolicy_TTL(t *testing.T) {
+	policy := TTLPolicy{
+		Default:   100 * time.Second,
+		Overrides: map[string]time.Duration{"list": 30 * time.Second, "search": 0},
+	}
+
+	tests := []struct {
+		name   string
+		policy TTLPolicy
+		key    string
+		ttl    time.Duration
+		want   time.Duration
+	}{
+		{name: "default", policy: policy, key: "article:1", want: 100 * time.Second},
+		{name: "override", policy: policy, key: "list:v:hash", want: 30 * time.Second},
+		{name: "zero override means no expiry", policy: policy, key: "search:v:hash", want: 0},
+		{name: "explicit ttl wins", policy: policy, key: "list:v:hash", ttl: 5 * time.Second, want: 5 * time.Second},
+		{name: "key without namespace", policy: policy, key: "list", want: 30 * time.Second},
+		{name: "zero default", policy: TTLPolicy{}, key: "article:1", want: 0},
+		{name: "jitter does not apply to explicit ttl", policy: TTLPolicy{Default: time.Minute, Jitter: 0.5}, key: "jwt:revoked:id", ttl: time.Hour, want: time.Hour},
+		{name: "jitter does not apply to no expiry", policy: TTLPolicy{Jitter: 0.5}, key: "article:1", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.TTL(tt.key, tt.ttl); got != tt.want {
+				t.Errorf("TTL(%q, %v) = %v, want %v", tt.key, tt.ttl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTTLPolicy_TTLJitter(t *testing.T) {
+	policy := TTLPolicy{Default: 100 * time.Second, Jitter: 0.1}
+
+	for range 1000 {
+		got := policy.TTL("article:1", 0)
+		if got < 90*time.Second || got > 110*time.Second {
+			t.Fatalf("TTL() = %v, want within ±10%% of 100s", got)
+		}
+	}
+}
+
+func TestEntity(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "article:1", want: "article"},
+		{key: "list:3:abc", want: "list"},
+		{key: "{lb}:rating", want: "{lb}"},
+		{key: "plain", want: "plain"},
+		{key: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := Entity(tt.key); got != tt.want {
+			t.Errorf("Entity(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
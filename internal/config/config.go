@@ -6,8 +6,11 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
@@ -16,35 +19,150 @@ import (
 // Config Структура конфигурации
 // Здесь используются следующие struct-теги (для анмаршалинга):
 // yaml — имя соответствующего параметра в Yaml-файле,
-// env-default — дефолтное значение,
-// env-required — делает параметры обязательными. Если такой параметр не указан, мы будем получать ошибку.
+// env — имена переменных окружения, которые переопределяют значение из файла (у всех параметров - с префиксом TEST_REDIS_,
+// например TEST_REDIS_CACHE_TTL для cache.ttl),
+// env-default — дефолтное значение (подставляется, только если значение нулевое; для параметров, у которых 0 - допустимое
+// значение, например cache.ttl, дефолт задается в defaultConfig, до чтения файла),
+// validate — правила проверки значения (github.com/go-playground/validator). Например, required делает параметр обязательным.
+// Собственные теги:
+// reload:"true" — параметр применяется без перезапуска при перечитывании конфигурации (SIGHUP, см. Reloader),
+// secret:"true" — значение не показывается (GET /admin/config).
 type Config struct {
-	Env         string `yaml:"env" env-default:"development"`
-	StoragePath string `yaml:"storage_path" env-required:"true"`
-	AppSecret   string `yaml:"app_secret" env-required:"true" env:"APP_SECRET"` // Секретный ключ, с помощью которого приложение будет проверять JWT-токены
-	Cache       `yaml:"cache_client"`
-	HTTPServer  `yaml:"http_server"`
+	Path               string        `yaml:"-"` // Путь до файла конфигурации (заполняется при загрузке)
+	Env                string        `yaml:"env" env:"TEST_REDIS_ENV" env-default:"development"`
+	StoragePath        string        `yaml:"storage_path" env:"TEST_REDIS_STORAGE_PATH" validate:"required"`
+	LogLevel           string        `yaml:"log_level" env:"TEST_REDIS_LOG_LEVEL" env-default:"" reload:"true"`                            // Уровень логирования: debug, info, warn, error ("" - по окружению)
+	StorageTimeout     time.Duration `yaml:"storage_timeout" env:"TEST_REDIS_STORAGE_TIMEOUT" validate:"gte=0"`                            // Ограничение времени одной операции хранилища (запросы к БД и кэшу)
+	AppSecret          string        `yaml:"app_secret" env:"TEST_REDIS_APP_SECRET,APP_SECRET" secret:"true" validate:"required"`          // Секретный ключ, с помощью которого приложение будет проверять JWT-токены
+	ViewsFlushInterval time.Duration `yaml:"views_flush_interval" env:"TEST_REDIS_VIEWS_FLUSH_INTERVAL" env-default:"10s" validate:"gt=0"` // Как часто просмотры статей переносятся из кэша в БД
+	Cache              `yaml:"cache"`
+	HTTPServer         `yaml:"http_server"`
 }
 
 type Cache struct {
-	Address  string `yaml:"address" env-default:"localhost:6379"`
-	Password string `yaml:"password" env-default:""`
-	DB       int    `yaml:"db" env-default:"0"`
+	Type             string                   `yaml:"type" env:"TEST_REDIS_CACHE_TYPE" env-default:"redis" validate:"oneof=redis memory none"`        // Тип кэша: redis, memory или none
+	Mode             string                   `yaml:"mode" env:"TEST_REDIS_CACHE_MODE" env-default:"single" validate:"oneof=single sentinel cluster"` // Режим подключения к Redis: single, sentinel или cluster
+	Address          string                   `yaml:"address" env:"TEST_REDIS_CACHE_ADDRESS" env-default:"localhost:6379"`
+	Addresses        []string                 `yaml:"addresses" env:"TEST_REDIS_CACHE_ADDRESSES"`                                          // Адреса Sentinel-серверов (sentinel) или узлов кластера (cluster)
+	MasterName       string                   `yaml:"master_name" env:"TEST_REDIS_CACHE_MASTER_NAME" validate:"required_if=Mode sentinel"` // Имя master-сервера (sentinel)
+	Username         string                   `yaml:"username" env:"TEST_REDIS_CACHE_USERNAME" env-default:""`
+	Password         string                   `yaml:"password" env:"TEST_REDIS_CACHE_PASSWORD" env-default:"" secret:"true"`
+	SentinelPassword string                   `yaml:"sentinel_password" env:"TEST_REDIS_CACHE_SENTINEL_PASSWORD" env-default:"" secret:"true"`
+	DB               int                      `yaml:"db" env:"TEST_REDIS_CACHE_DB" env-default:"0" validate:"gte=0"`
+	PoolSize         int                      `yaml:"pool_size" env:"TEST_REDIS_CACHE_POOL_SIZE" env-default:"0" validate:"gte=0"` // 0 - значение go-redis по умолчанию
+	MinIdleConns     int                      `yaml:"min_idle_conns" env:"TEST_REDIS_CACHE_MIN_IDLE_CONNS" env-default:"0" validate:"gte=0"`
+	TLS              CacheTLS                 `yaml:"tls"`
+	StartupPolicy    string                   `yaml:"startup_policy" env:"TEST_REDIS_CACHE_STARTUP_POLICY" env-default:"degrade" validate:"oneof=fail degrade"` // Если Redis недоступен при запуске: fail - не запускаться, degrade - работать без него
+	StartupRetries   int                      `yaml:"startup_retries" env:"TEST_REDIS_CACHE_STARTUP_RETRIES" env-default:"5" validate:"gte=1"`                  // Количество попыток PING при запуске
+	StartupBackoff   time.Duration            `yaml:"startup_backoff" env:"TEST_REDIS_CACHE_STARTUP_BACKOFF" validate:"gte=0"`                                  // Начальная пауза между попытками (удваивается)
+	MemorySize       int                      `yaml:"memory_size" env:"TEST_REDIS_CACHE_MEMORY_SIZE" env-default:"10000" validate:"gt=0"`                       // Максимальное количество ключей для кэша в памяти (type: memory)
+	TTL              time.Duration            `yaml:"ttl" env:"TEST_REDIS_CACHE_TTL" reload:"true" validate:"gte=0"`                                            // Время жизни записей в кэше по умолчанию
+	TTLOverrides     map[string]time.Duration `yaml:"ttl_overrides" env:"TEST_REDIS_CACHE_TTL_OVERRIDES" reload:"true" validate:"dive,gte=0"`                   // Время жизни для отдельных сущностей (article, ...)
+	TTLJitter        float64                  `yaml:"ttl_jitter" env:"TEST_REDIS_CACHE_TTL_JITTER" env-default:"0" reload:"true" validate:"gte=0,lt=1"`         // Доля случайного разброса времени жизни (0.1 - ±10%)
+	NegativeTTL      time.Duration            `yaml:"negative_ttl" env:"TEST_REDIS_CACHE_NEGATIVE_TTL" reload:"true" validate:"gte=0"`                          // Время жизни отметки об отсутствии записи (негативное кэширование, 0 - отключено)
+	Prefix           string                   `yaml:"prefix" env:"TEST_REDIS_CACHE_PREFIX" env-default:""`                                                      // Пространство имен ключей (например, "dev:"), чтобы несколько окружений могли использовать один Redis
+	Serialization    string                   `yaml:"serialization" env:"TEST_REDIS_CACHE_SERIALIZATION" env-default:"json" validate:"oneof=json gob"`          // Формат хранения значений: json или gob
+	Timeout          time.Duration            `yaml:"timeout" env:"TEST_REDIS_CACHE_TIMEOUT" validate:"gte=0"`                                                  // Ограничение времени одной команды Redis
+}
+
+// CacheTLS Параметры TLS-подключения к Redis
+type CacheTLS struct {
+	Enabled            bool   `yaml:"enabled" env:"TEST_REDIS_CACHE_TLS_ENABLED" env-default:"false"`
+	CAFile             string `yaml:"ca_file" env:"TEST_REDIS_CACHE_TLS_CA_FILE"`
+	CertFile           string `yaml:"cert_file" env:"TEST_REDIS_CACHE_TLS_CERT_FILE" validate:"required_with=KeyFile"`
+	KeyFile            string `yaml:"key_file" env:"TEST_REDIS_CACHE_TLS_KEY_FILE" validate:"required_with=CertFile"`
+	ServerName         string `yaml:"server_name" env:"TEST_REDIS_CACHE_TLS_SERVER_NAME"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify" env:"TEST_REDIS_CACHE_TLS_INSECURE_SKIP_VERIFY" env-default:"false"`
 }
 
 type HTTPServer struct {
-	Address     string        `yaml:"address" env-default:"localhost:8500"`
-	Timeout     time.Duration `yaml:"timeout" env-default:"4s"`
-	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
-	User        string        `yaml:"user" env-required:"true"`
-	Password    string        `yaml:"password" env-required:"true" env:"HTTP_SERVER_PASSWORD"`
+	Address         string        `yaml:"address" env:"TEST_REDIS_HTTP_SERVER_ADDRESS" env-default:"localhost:8500" validate:"required"`
+	Timeout         time.Duration `yaml:"timeout" env:"TEST_REDIS_HTTP_SERVER_TIMEOUT" validate:"gte=0"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" env:"TEST_REDIS_HTTP_SERVER_IDLE_TIMEOUT" validate:"gte=0"`
+	User            string        `yaml:"user" env:"TEST_REDIS_HTTP_SERVER_USER" validate:"required"`
+	Password        string        `yaml:"password" env:"TEST_REDIS_HTTP_SERVER_PASSWORD,HTTP_SERVER_PASSWORD" secret:"true" validate:"required"`
+	TokenTTL        time.Duration `yaml:"token_ttl" env:"TEST_REDIS_HTTP_SERVER_TOKEN_TTL" env-default:"1h" validate:"gt=0"`                // Срок действия JWT-токена
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"TEST_REDIS_HTTP_SERVER_SHUTDOWN_TIMEOUT" env-default:"10s" validate:"gt=0"` // Сколько ждать завершения обработки запросов и фоновых задач при остановке
+	RateLimit       RateLimit     `yaml:"rate_limit" reload:"true"`
+	CORS            CORS          `yaml:"cors" reload:"true"`
+}
+
+// CORS Параметры CORS
+type CORS struct {
+	AllowedOrigins []string `yaml:"allowed_origins" env:"TEST_REDIS_HTTP_SERVER_CORS_ALLOWED_ORIGINS" env-default:"https://*,http://*"` // Разрешенные источники запросов (можно с '*')
+}
+
+// RateLimit Ограничение частоты запросов (счетчики хранятся в кэше, в Redis - общие для всех экземпляров сервиса)
+type RateLimit struct {
+	Enabled  bool            `yaml:"enabled" env:"TEST_REDIS_HTTP_SERVER_RATE_LIMIT_ENABLED" env-default:"false"`
+	Requests int             `yaml:"requests" env:"TEST_REDIS_HTTP_SERVER_RATE_LIMIT_REQUESTS" env-default:"100" validate:"gt=0"` // Не больше requests запросов за window
+	Window   time.Duration   `yaml:"window" env:"TEST_REDIS_HTTP_SERVER_RATE_LIMIT_WINDOW" env-default:"1m" validate:"gt=0"`
+	KeyBy    []string        `yaml:"key_by" env:"TEST_REDIS_HTTP_SERVER_RATE_LIMIT_KEY_BY" env-default:"ip" validate:"dive,oneof=ip user route"` // По каким признакам считаются запросы: ip, user, route (можно несколько)
+	Routes   RouteRateLimits `yaml:"routes" env:"TEST_REDIS_HTTP_SERVER_RATE_LIMIT_ROUTES" validate:"dive"`                                      // Отдельные ограничения для маршрутов, например "POST /articles"
+}
+
+// RouteRateLimit Ограничение частоты запросов для маршрута
+type RouteRateLimit struct {
+	Requests int           `yaml:"requests" validate:"gte=0"`
+	Window   time.Duration `yaml:"window" validate:"gte=0"`
+}
+
+// RouteRateLimits Ограничения частоты запросов для маршрутов
+type RouteRateLimits map[string]RouteRateLimit
+
+// SetValue Разбор значения из переменной окружения (cleanenv.Setter).
+// Формат: "маршрут=requests/window;...", например "POST /articles=10/1m;GET /search=30/1m"
+func (r *RouteRateLimits) SetValue(s string) error {
+	routes := make(RouteRateLimits)
+	for _, item := range strings.Split(s, ";") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+
+		route, limit, ok := cutLast(item, "=")
+		if !ok {
+			return fmt.Errorf("invalid route limit %q: expected route=requests/window", item)
+		}
+		requests, window, ok := strings.Cut(limit, "/")
+		if !ok {
+			return fmt.Errorf("invalid route limit %q: expected route=requests/window", item)
+		}
+
+		var (
+			l   RouteRateLimit
+			err error
+		)
+		if l.Requests, err = strconv.Atoi(strings.TrimSpace(requests)); err != nil {
+			return fmt.Errorf("invalid route limit %q: %w", item, err)
+		}
+		if l.Window, err = time.ParseDuration(strings.TrimSpace(window)); err != nil {
+			return fmt.Errorf("invalid route limit %q: %w", item, err)
+		}
+		routes[strings.TrimSpace(route)] = l
+	}
+
+	*r = routes
+
+	return nil
+}
+
+// cutLast Как strings.Cut, но по последнему вхождению sep
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+
+	return s, "", false
 }
 
 // MustLoadFetchFlag загрузка конфигурации из ENV-переменной CONFIG_PATH или файла конфигурации
 func MustLoadFetchFlag() *Config {
-	// получаем путь до конфиг-файла из ENV-переменной CONFIG_PATH
-	configPath := fetchConfigPath()
+	// получаем путь до конфиг-файла из параметра --config или ENV-переменной CONFIG_PATH
+	return MustLoadPath(FetchConfigPath())
+}
 
+// MustLoadPath загрузка конфигурации из файла configPath
+func MustLoadPath(configPath string) *Config {
 	if configPath == "" {
 		log.Fatal("config path is empty")
 	}
@@ -55,19 +173,63 @@ func MustLoadFetchFlag() *Config {
 	}
 
 	//читаем конфиг файл в структуру
-	var cfg Config
-	err := cleanenv.ReadConfig(configPath, &cfg)
+	cfg, err := Load(configPath)
 	if err != nil {
 		log.Fatalf("error reading config file: %s", err)
 	}
 
-	return &cfg
+	return cfg
 }
 
-// fetchConfigPath fetches config path from command line flag or environment variable.
+// Load Читает файл конфигурации, переопределяет значения из переменных окружения и проверяет результат.
+// Ошибки не прерывают проверку: возвращаются все найденные сразу (*ValidationError).
+// В отличие от MustLoad, не завершает работу приложения при ошибке - используется и при перечитывании конфигурации
+func Load(path string) (*Config, error) {
+	const op = "config.Load"
+
+	cfg := defaultConfig()
+
+	problems, err := decodeFile(path, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// cleanenv прекращает чтение переменных на первой ошибке, поэтому они проверяются заранее
+	if envProblems := checkEnv(&cfg); len(envProblems) > 0 {
+		problems = append(problems, envProblems...)
+	} else if err := cleanenv.ReadEnv(&cfg); err != nil {
+		problems = append(problems, err.Error())
+	} else {
+		problems = append(problems, cfg.validate()...)
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%s: %w", op, &ValidationError{Path: path, Problems: problems})
+	}
+	cfg.Path = path
+
+	return &cfg, nil
+}
+
+// defaultConfig Значения по умолчанию для параметров, у которых 0 имеет смысл (0 - без ограничения времени).
+// Через env-default их задать нельзя: cleanenv подставил бы дефолт и вместо явно указанного в файле 0
+func defaultConfig() Config {
+	var cfg Config
+	cfg.StorageTimeout = 3 * time.Second
+	cfg.Cache.StartupBackoff = 500 * time.Millisecond
+	cfg.Cache.TTL = 100 * time.Second
+	cfg.Cache.NegativeTTL = 10 * time.Second
+	cfg.Cache.Timeout = 500 * time.Millisecond
+	cfg.HTTPServer.Timeout = 4 * time.Second
+	cfg.HTTPServer.IdleTimeout = 60 * time.Second
+
+	return cfg
+}
+
+// FetchConfigPath fetches config path from command line flag or environment variable.
 // Priority: flag > env > default.
 // Default value is empty string.
-func fetchConfigPath() string {
+func FetchConfigPath() string {
 	var res string
 
 	flag.StringVar(&res, "config", "", "path to config file")
@@ -104,11 +266,10 @@ func MustLoad() *Config {
 	}
 
 	//читаем конфиг файл в структуру
-	var cfg Config
-	err := cleanenv.ReadConfig(configPath, &cfg)
+	cfg, err := Load(configPath)
 	if err != nil {
 		log.Fatalf("error reading config file: %s", err)
 	}
 
-	return &cfg
+	return cfg
 }
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// baseConfig Минимальная корректная конфигурация: только обязательные параметры
+const baseConfig = `
+storage_path: ./storage.db
+app_secret: secret
+http_server:
+  user: user
+  password: password
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		env    map[string]string
+		check  func(t *testing.T, cfg *Config)
+	}{
+		{
+			name:   "defaults",
+			config: baseConfig,
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Cache.TTL != 100*time.Second || cfg.Cache.NegativeTTL != 10*time.Second || cfg.StorageTimeout != 3*time.Second {
+					t.Errorf("ttl = %v, negative_ttl = %v, storage_timeout = %v", cfg.Cache.TTL, cfg.Cache.NegativeTTL, cfg.StorageTimeout)
+				}
+				if cfg.Cache.Type != "redis" || cfg.HTTPServer.Address != "localhost:8500" || cfg.HTTPServer.TokenTTL != time.Hour {
+					t.Errorf("type = %q, address = %q, token_ttl = %v", cfg.Cache.Type, cfg.HTTPServer.Address, cfg.HTTPServer.TokenTTL)
+				}
+			},
+		},
+		{
+			name:   "explicit zero durations",
+			config: baseConfig + "cache:\n  ttl: 0s\n  negative_ttl: 0s\n  timeout: 0s\nstorage_timeout: 0s\n",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Cache.TTL != 0 || cfg.Cache.NegativeTTL != 0 || cfg.Cache.Timeout != 0 || cfg.StorageTimeout != 0 {
+					t.Errorf("ttl = %v, negative_ttl = %v, timeout = %v, storage_timeout = %v",
+						cfg.Cache.TTL, cfg.Cache.NegativeTTL, cfg.Cache.Timeout, cfg.StorageTimeout)
+				}
+			},
+		},
+		{
+			name:   "env overrides file",
+			config: baseConfig + "cache:\n  ttl: 50s\n",
+			env: map[string]string{
+				"TEST_REDIS_CACHE_TTL":                       "0",
+				"TEST_REDIS_HTTP_SERVER_RATE_LIMIT_REQUESTS": "7",
+				"TEST_REDIS_HTTP_SERVER_RATE_LIMIT_ROUTES":   "POST /articles=10/1m;GET /search=30/30s",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Cache.TTL != 0 {
+					t.Errorf("ttl = %v, want 0", cfg.Cache.TTL)
+				}
+				if cfg.HTTPServer.RateLimit.Requests != 7 {
+					t.Errorf("rate_limit.requests = %d, want 7", cfg.HTTPServer.RateLimit.Requests)
+				}
+				want := RouteRateLimits{
+					"POST /articles": {Requests: 10, Window: time.Minute},
+					"GET /search":    {Requests: 30, Window: 30 * time.Second},
+				}
+				if len(cfg.HTTPServer.RateLimit.Routes) != len(want) {
+					t.Fatalf("rate_limit.routes = %v, want %v", cfg.HTTPServer.RateLimit.Routes, want)
+				}
+				for route, limit := range want {
+					if cfg.HTTPServer.RateLimit.Routes[route] != limit {
+						t.Errorf("rate_limit.routes[%q] = %+v, want %+v", route, cfg.HTTPServer.RateLimit.Routes[route], limit)
+					}
+				}
+			},
+		},
+		{
+			name:   "legacy secret env",
+			config: strings.Replace(baseConfig, "app_secret: secret\n", "", 1),
+			env:    map[string]string{"APP_SECRET": "from-env"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.AppSecret != "from-env" {
+					t.Errorf("app_secret = %q, want from-env", cfg.AppSecret)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			path := writeConfig(t, tt.config)
+
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Path != path {
+				t.Errorf("Path = %q, want %q", cfg.Path, path)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		env      map[string]string
+		problems []string
+	}{
+		{
+			name:     "missing required",
+			config:   "cache:\n  type: memory\n",
+			problems: []string{"storage_path: is required", "app_secret: is required", "http_server.user: is required", "http_server.password: is required"},
+		},
+		{
+			name:     "unknown field",
+			config:   baseConfig + "unknown: 1\n",
+			problems: []string{"field unknown not found"},
+		},
+		{
+			name:   "wrong types and values are reported together",
+			config: baseConfig + "cache:\n  ttl: 10x\n  type: disk\n  ttl_jitter: 1\nlog_level: loud\n",
+			problems: []string{
+				"time.Duration",
+				"cache.type: must be one of: redis, memory, none (got disk)",
+				"cache.ttl_jitter: must be less than 1",
+				`log_level: unknown level "loud"`,
+			},
+		},
+		{
+			name:     "negative duration",
+			config:   baseConfig + "cache:\n  negative_ttl: -1s\n",
+			problems: []string{"cache.negative_ttl: must be greater than or equal to 0"},
+		},
+		{
+			name:     "required_if",
+			config:   baseConfig + "cache:\n  mode: sentinel\n",
+			problems: []string{"cache.master_name: is required when mode is sentinel"},
+		},
+		{
+			name:   "invalid env values",
+			config: baseConfig,
+			env: map[string]string{
+				"TEST_REDIS_CACHE_TTL":                     "soon",
+				"TEST_REDIS_HTTP_SERVER_RATE_LIMIT_ROUTES": "POST /articles",
+			},
+			problems: []string{"cache.ttl (env TEST_REDIS_CACHE_TTL)", "http_server.rate_limit.routes (env TEST_REDIS_HTTP_SERVER_RATE_LIMIT_ROUTES)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			_, err := Load(writeConfig(t, tt.config))
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Load() error = %v, want *ValidationError", err)
+			}
+			for _, want := range tt.problems {
+				if !slices.ContainsFunc(verr.Problems, func(p string) bool { return strings.Contains(p, want) }) {
+					t.Errorf("problems %q do not mention %q", verr.Problems, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRouteRateLimits_SetValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    RouteRateLimits
+		wantErr bool
+	}{
+		{name: "empty", value: "", want: RouteRateLimits{}},
+		{name: "one", value: "POST /articles=10/1m", want: RouteRateLimits{"POST /articles": {Requests: 10, Window: time.Minute}}},
+		{name: "trailing separator and spaces", value: " GET /search = 5 / 10s ;", want: RouteRateLimits{"GET /search": {Requests: 5, Window: 10 * time.Second}}},
+		{name: "no limit", value: "POST /articles", wantErr: true},
+		{name: "no window", value: "POST /articles=10", wantErr: true},
+		{name: "bad requests", value: "POST /articles=ten/1m", wantErr: true},
+		{name: "bad window", value: "POST /articles=10/minute", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got RouteRateLimits
+			err := got.SetValue(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetValue(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("SetValue(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+			for route, limit := range tt.want {
+				if got[route] != limit {
+					t.Errorf("SetValue(%q)[%q] = %+v, want %+v", tt.value, route, got[route], limit)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redactedValue Значение, которое показывается вместо секрета
+const redactedValue = "***"
+
+// Redacted Конфигурация в виде дерева параметров с именами как в yaml-файле.
+// Значения параметров с тегом secret:"true" заменены на "***" (пустые значения остаются пустыми)
+func (c *Config) Redacted() (map[string]any, error) {
+	const op = "config.Redacted"
+
+	res := *c
+	redact(reflect.ValueOf(&res).Elem())
+
+	// yaml дает имена параметров как в файле конфигурации и длительности в читаемом виде (10s)
+	data, err := yaml.Marshal(&res)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var tree map[string]any
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tree, nil
+}
+
+// redact Рекурсивно заменяет значения строковых полей с тегом secret:"true"
+func redact(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		switch {
+		case field.Tag.Get("secret") == "true" && field.Type.Kind() == reflect.String:
+			if v.Field(i).String() != "" {
+				v.Field(i).SetString(redactedValue)
+			}
+		case field.Type.Kind() == reflect.Struct:
+			redact(v.Field(i))
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Reloader Перечитывание файла конфигурации без перезапуска сервиса.
+// Применяются только параметры с тегом reload:"true" (уровень логирования, время жизни записей кэша,
+// ограничение частоты запросов, CORS). Изменения остальных параметров (адрес сервера, путь до БД и т.п.)
+// требуют перезапуска: они игнорируются, действующие значения сохраняются
+type Reloader struct {
+	mu          sync.Mutex // Перечитывания выполняются по одному
+	current     atomic.Pointer[Config]
+	subscribers []func(cfg *Config)
+}
+
+// NewReloader Конструктор Reloader. cfg - конфигурация, с которой запущен сервис
+func NewReloader(cfg *Config) *Reloader {
+	r := &Reloader{}
+	r.current.Store(cfg)
+
+	return r
+}
+
+// Current Действующая конфигурация. Возвращаемое значение изменять нельзя
+func (r *Reloader) Current() *Config {
+	return r.current.Load()
+}
+
+// OnReload Подписка на изменение конфигурации: fn вызывается с новой действующей конфигурацией.
+// Подписываться нужно до первого перечитывания
+func (r *Reloader) OnReload(fn func(cfg *Config)) {
+	r.subscribers = append(r.subscribers, fn)
+}
+
+// Reload Перечитывает файл конфигурации и применяет изменения параметров с тегом reload:"true".
+// ignored - параметры (в формате yaml, например http_server.address), изменения которых требуют перезапуска.
+// При ошибке чтения или проверки файла действующая конфигурация не меняется
+func (r *Reloader) Reload() (ignored []string, err error) {
+	const op = "config.Reloader.Reload"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur := r.current.Load()
+
+	next, err := Load(cur.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	effective, ignored := merge(cur, next)
+	r.current.Store(effective)
+
+	for _, fn := range r.subscribers {
+		fn(effective)
+	}
+
+	return ignored, nil
+}
+
+// merge Действующая конфигурация после перечитывания: параметры с тегом reload:"true" берутся из next,
+// остальные - из cur. ignored - измененные параметры, которые не были применены
+func merge(cur, next *Config) (effective *Config, ignored []string) {
+	res := *cur
+	mergeStruct(reflect.ValueOf(&res).Elem(), reflect.ValueOf(next).Elem(), "", &ignored)
+
+	return &res, ignored
+}
+
+// mergeStruct Рекурсивно переносит в dst поля src с тегом reload:"true"
+func mergeStruct(dst, src reflect.Value, path string, ignored *[]string) {
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := yamlName(field)
+		if name == "-" {
+			continue
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+
+		switch {
+		case field.Tag.Get("reload") == "true":
+			dst.Field(i).Set(src.Field(i))
+		case field.Type.Kind() == reflect.Struct:
+			mergeStruct(dst.Field(i), src.Field(i), name, ignored)
+		case !reflect.DeepEqual(dst.Field(i).Interface(), src.Field(i).Interface()):
+			*ignored = append(*ignored, name)
+		}
+	}
+}
+
+// yamlName Имя параметра в yaml-файле
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+
+	return name
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ilyakaznacheev/cleanenv"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError Все ошибки, найденные в файле конфигурации и переменных окружения
+type ValidationError struct {
+	Path     string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid config %s (%d errors):\n  - %s", e.Path, len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// decodeFile Чтение yaml-файла в cfg. Неизвестные параметры и значения неверного типа (например, "10x" для длительности)
+// возвращаются списком problems, чтение остальных параметров при этом продолжается.
+// err - файл не удалось прочитать или разобрать
+func decodeFile(path string, cfg *Config) (problems []string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+
+	err = dec.Decode(cfg)
+
+	var typeErr *yaml.TypeError
+	switch {
+	case err == nil, errors.Is(err, io.EOF): // пустой файл - все значения по умолчанию
+		return nil, nil
+	case errors.As(err, &typeErr):
+		return typeErr.Errors, nil
+	default:
+		return nil, fmt.Errorf("config file parsing error: %w", err)
+	}
+}
+
+// checkEnv Проверка значений переменных окружения, заданных для параметров конфигурации.
+// Каждая переменная разбирается отдельно тем же способом, что и в cleanenv, чтобы найти все ошибки сразу
+func checkEnv(cfg *Config) []string {
+	var problems []string
+
+	walkFields(reflect.TypeOf(cfg).Elem(), "", func(field reflect.StructField, path string) {
+		envs, ok := field.Tag.Lookup("env")
+		if !ok {
+			return
+		}
+
+		for _, name := range strings.Split(envs, ",") {
+			if _, ok := os.LookupEnv(name); !ok {
+				continue
+			}
+
+			tag := fmt.Sprintf(`env:%q`, name)
+			if sep, ok := field.Tag.Lookup("env-separator"); ok {
+				tag += fmt.Sprintf(` env-separator:%q`, sep)
+			}
+			probe := reflect.New(reflect.StructOf([]reflect.StructField{
+				{Name: "Value", Type: field.Type, Tag: reflect.StructTag(tag)},
+			}))
+
+			if err := cleanenv.ReadEnv(probe.Interface()); err != nil {
+				msg := strings.TrimPrefix(err.Error(), fmt.Sprintf("parsing field Value env %s: ", name))
+				problems = append(problems, fmt.Sprintf("%s (env %s): %s", path, name, msg))
+			}
+			break // как и cleanenv, используем первую заданную переменную из списка
+		}
+	})
+
+	return problems
+}
+
+// walkFields Обход параметров конфигурации (кроме вложенных структур) с их именами в формате yaml (cache.ttl)
+func walkFields(t reflect.Type, path string, fn func(field reflect.StructField, path string)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := yamlName(field)
+		if name == "-" {
+			continue
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			walkFields(field.Type, name, fn)
+			continue
+		}
+		fn(field, name)
+	}
+}
+
+// validate Проверка значений по правилам из тегов validate и тех, что тегами не выразить
+func (c *Config) validate() []string {
+	var problems []string
+
+	v := validator.New()
+	v.RegisterTagNameFunc(yamlName)
+
+	var errs validator.ValidationErrors
+	if err := v.Struct(c); errors.As(err, &errs) {
+		for _, err := range errs {
+			problems = append(problems, fieldProblem(err))
+		}
+	} else if err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if c.LogLevel != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+			problems = append(problems, fmt.Sprintf("log_level: unknown level %q (expected debug, info, warn or error)", c.LogLevel))
+		}
+	}
+
+	return problems
+}
+
+// fieldProblem Описание ошибки проверки параметра. Имя параметра - в формате yaml (http_server.rate_limit.window)
+func fieldProblem(err validator.FieldError) string {
+	// Namespace начинается с имени корневой структуры: Config.cache.ttl
+	_, name, _ := strings.Cut(err.Namespace(), ".")
+
+	switch err.ActualTag() {
+	case "required":
+		return name + ": is required"
+	case "required_if":
+		field, value, _ := strings.Cut(err.Param(), " ")
+		return fmt.Sprintf("%s: is required when %s is %s", name, snakeCase(field), value)
+	case "required_with":
+		return fmt.Sprintf("%s: is required when %s is set", name, snakeCase(err.Param()))
+	}
+
+	var msg string
+	switch err.ActualTag() {
+	case "oneof":
+		msg = "must be one of: " + strings.ReplaceAll(err.Param(), " ", ", ")
+	case "gt":
+		msg = "must be greater than " + err.Param()
+	case "gte":
+		msg = "must be greater than or equal to " + err.Param()
+	case "lt":
+		msg = "must be less than " + err.Param()
+	default:
+		msg = "is not valid"
+	}
+
+	return fmt.Sprintf("%s: %s (got %v)", name, msg, err.Value())
+}
+
+// snakeCase Имя поля структуры в формате yaml-параметра: KeyFile -> key_file
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
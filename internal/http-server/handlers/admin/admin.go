@@ -0,0 +1,199 @@
+//internal/http-server/handlers/admin/admin.go
+
+// Служебные обработчики: управление кэшем, просмотр конфигурации. Подключаются в группе /admin под Basic-аутентификацией
+package admin
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"test-redis/internal/cache"
+	resp "test-redis/internal/lib/api/response"
+)
+
+const (
+	defaultKeysLimit = 100
+	maxKeysLimit     = 10000
+	defaultTTLSample = 1000
+)
+
+// KeysResponse Ключи кэша
+type KeysResponse struct {
+	Prefix string   `json:"prefix"`
+	Keys   []string `json:"keys"`
+}
+
+// KeyResponse Сведения о ключе кэша
+type KeyResponse struct {
+	cache.KeyInfo
+	TTLText string `json:"ttl_text,omitempty"` // TTL в читаемом виде
+}
+
+// FlushResponse Результат удаления ключей
+type FlushResponse struct {
+	Prefix  string `json:"prefix"`
+	Deleted int    `json:"deleted"`
+}
+
+// TTLPolicy Настройки времени жизни записей кэша
+type TTLPolicy struct {
+	Default     string            `json:"default"`
+	Overrides   map[string]string `json:"overrides,omitempty"`
+	Jitter      float64           `json:"jitter"`
+	NegativeTTL string            `json:"negative_ttl"`
+}
+
+// TTLResponse Отчет о времени жизни ключей
+type TTLResponse struct {
+	Policy   TTLPolicy                  `json:"policy"`
+	Sample   int                        `json:"sample"` // Сколько ключей просмотрено (не больше)
+	Entities map[string]cache.EntityTTL `json:"entities"`
+}
+
+// TTLSettings is an interface for getting the current cache TTL settings.
+// Настройки могут измениться при перечитывании конфигурации
+type TTLSettings interface {
+	TTLPolicy() cache.TTLPolicy
+	NegativeTTL() time.Duration
+}
+
+// ListKeys Ключи кэша с префиксом. Параметры: prefix, limit
+func ListKeys(log *slog.Logger, inspector cache.Inspector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.ListKeys"
+
+		prefix := r.URL.Query().Get("prefix")
+		limit, ok := queryLimit(w, r, "limit", defaultKeysLimit)
+		if !ok {
+			return
+		}
+
+		keys, err := inspector.Scan(r.Context(), prefix, limit)
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+		if keys == nil {
+			keys = []string{}
+		}
+
+		resp.OK(w, r, KeysResponse{Prefix: prefix, Keys: keys})
+	}
+}
+
+// InspectKey Сведения о ключе кэша: тип, оставшееся время жизни, размер, начало значения. Параметр: key
+func InspectKey(log *slog.Logger, inspector cache.Inspector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.InspectKey"
+
+		key := r.URL.Query().Get("key")
+		if key == "" {
+			resp.Error(w, r, http.StatusBadRequest, "key is required")
+			return
+		}
+
+		infos, err := inspector.Inspect(r.Context(), key)
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+
+		info := infos[0]
+		if !info.Exists {
+			resp.Error(w, r, http.StatusNotFound, "not found")
+			return
+		}
+
+		res := KeyResponse{KeyInfo: info}
+		if info.TTL > 0 {
+			res.TTLText = info.TTL.Round(time.Millisecond).String()
+		}
+
+		resp.OK(w, r, res)
+	}
+}
+
+// FlushPrefix Удалить ключи кэша с префиксом. Параметр: prefix (обязателен). Префикс должен начинаться
+// с одного из namespaces - служебные данные в кэше (счетчики просмотров, рейтинги, ограничения частоты) удалять нельзя
+func FlushPrefix(log *slog.Logger, inspector cache.Inspector, namespaces []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.FlushPrefix"
+
+		prefix := r.URL.Query().Get("prefix")
+		if prefix == "" {
+			resp.Error(w, r, http.StatusBadRequest, "prefix is required")
+			return
+		}
+		if !slices.ContainsFunc(namespaces, func(ns string) bool { return strings.HasPrefix(prefix, ns) }) {
+			resp.Error(w, r, http.StatusBadRequest, "prefix must start with one of: "+strings.Join(namespaces, ", "))
+			return
+		}
+
+		deleted, err := inspector.DeletePrefix(r.Context(), prefix)
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+
+		log.Warn("cache flushed", slog.String("prefix", prefix), slog.Int("deleted", deleted))
+
+		resp.OK(w, r, FlushResponse{Prefix: prefix, Deleted: deleted})
+	}
+}
+
+// TTLReport Настройки времени жизни и фактическое оставшееся время жизни ключей по сущностям. Параметры: prefix, sample
+func TTLReport(log *slog.Logger, inspector cache.Inspector, settings TTLSettings) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.TTLReport"
+
+		sample, ok := queryLimit(w, r, "sample", defaultTTLSample)
+		if !ok {
+			return
+		}
+
+		entities, err := cache.TTLReport(r.Context(), inspector, r.URL.Query().Get("prefix"), sample)
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+
+		policy := settings.TTLPolicy()
+		res := TTLResponse{
+			Policy: TTLPolicy{
+				Default:     policy.Default.String(),
+				Jitter:      policy.Jitter,
+				NegativeTTL: settings.NegativeTTL().String(),
+			},
+			Sample:   sample,
+			Entities: entities,
+		}
+		if len(policy.Overrides) > 0 {
+			res.Policy.Overrides = make(map[string]string, len(policy.Overrides))
+			for entity, ttl := range policy.Overrides {
+				res.Policy.Overrides[entity] = ttl.String()
+			}
+		}
+
+		resp.OK(w, r, res)
+	}
+}
+
+// queryLimit Разбор ограничения количества ключей. При ошибке сам пишет ответ 400 и возвращает false
+func queryLimit(w http.ResponseWriter, r *http.Request, name string, def int) (int, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, true
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 || limit > maxKeysLimit {
+		resp.Error(w, r, http.StatusBadRequest, name+" must be between 1 and "+strconv.Itoa(maxKeysLimit))
+		return 0, false
+	}
+
+	return limit, true
+}
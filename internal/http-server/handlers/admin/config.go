@@ -0,0 +1,32 @@
+//internal/http-server/handlers/admin/config.go
+
+package admin
+
+import (
+	"log/slog"
+	"net/http"
+
+	"test-redis/internal/config"
+	resp "test-redis/internal/lib/api/response"
+)
+
+// ConfigProvider is an interface for getting the effective configuration
+// (с учетом изменений, примененных при перечитывании файла)
+type ConfigProvider interface {
+	Current() *config.Config
+}
+
+// Config Действующая конфигурация сервиса. Секреты (пароли, ключ JWT) скрыты
+func Config(log *slog.Logger, provider ConfigProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.Config"
+
+		cfg, err := provider.Current().Redacted()
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+
+		resp.OK(w, r, cfg)
+	}
+}
@@ -3,28 +3,32 @@
 package article
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/render"
-	"io/ioutil"
+	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
 	resp "test-redis/internal/lib/api/response"
 	"test-redis/internal/lib/logger/sl"
 	"test-redis/internal/models"
-	"test-redis/internal/storage"
 )
 
 // DataGetter is an interface for getting data by Id.
 //
 //go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=DataGetter
 type DataGetter interface {
-	GetData(id string) (string, error)
-	GetRandomData() ([]models.ArticleInfo, error)
+	GetData(ctx context.Context, id int64) (models.ArticleInfo, error)
+	GetRandomData(ctx context.Context, count int, weighted bool) ([]models.ArticleInfo, error)
+	RecordView(ctx context.Context, id int64) error // Учесть просмотр статьи (рейтинг активности)
 }
 
-// GetRandArticles Получить статьи по их ид
+// maxRandomCount Максимальное количество случайных статей в одном запросе
+const maxRandomCount = 100
+
+// GetRandArticles Получить случайные статьи.
+// Параметры: count - количество разных статей (по умолчанию 1), weighted=true - чаще выбирать статьи с высоким рейтингом
 func GetRandArticles(log *slog.Logger, dataGetter DataGetter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.article.GetArticles"
@@ -35,20 +39,25 @@ func GetRandArticles(log *slog.Logger, dataGetter DataGetter) http.HandlerFunc {
 		//	slog.String("request_id", middleware.GetReqID(r.Context())),
 		//)
 
-		//var resData []models.ArticleInfo
+		count, err := queryInt(r, "count", 1)
+		if err != nil || count <= 0 || count > maxRandomCount {
+			log.Info("invalid count", slog.String("count", r.URL.Query().Get("count")))
+			resp.Error(w, r, http.StatusBadRequest, "count must be between 1 and "+strconv.Itoa(maxRandomCount))
+			return
+		}
 
-		// Находим статью в БД
-		resData, err := dataGetter.GetRandomData()
-		if errors.Is(err, storage.ErrDataNotFound) {
-			// Не нашли, сообщаем об этом клиенту
-			log.Info("data not found")
-			render.JSON(w, r, resp.Error("not found"))
+		weighted, err := queryBool(r, "weighted")
+		if err != nil {
+			log.Info("invalid weighted", slog.String("weighted", r.URL.Query().Get("weighted")))
+			resp.Error(w, r, http.StatusBadRequest, "weighted must be a boolean")
 			return
 		}
+
+		// Находим статьи (ид - в БД, сами статьи - через кэш)
+		resData, err := dataGetter.GetRandomData(r.Context(), count, weighted)
 		if err != nil {
-			// Не удалось осуществить поиск
-			log.Error("failed to get data", sl.Err(err))
-			render.JSON(w, r, resp.Error("internal error"))
+			// Не нашли (статей нет) или не удалось осуществить поиск
+			resp.Fail(log, w, r, op, err)
 			return
 		}
 
@@ -56,8 +65,7 @@ func GetRandArticles(log *slog.Logger, dataGetter DataGetter) http.HandlerFunc {
 		log.Info("got data")
 
 		//пишем в ответ
-		res, err := json.Marshal(resData)
-		w.Write(res)
+		resp.OK(w, r, resData)
 	}
 }
 
@@ -74,32 +82,27 @@ func GetArticle(log *slog.Logger, dataGetter DataGetter) http.HandlerFunc {
 
 		// Роутер chi позволяет делать вот такие финты - получать GET-параметры по их именам.
 		// Имена определяются при добавлении хэндлера в роутер.
-		articleId := chi.URLParam(r, "article_id")
-		if articleId == "" {
-			log.Info("article_id is empty")
-			render.JSON(w, r, resp.Error("not found"))
+		articleId, ok := parseArticleId(log, w, r)
+		if !ok {
 			return
 		}
 
-		// Находим статью в БД
-		resData, err := dataGetter.GetData(articleId)
-		if errors.Is(err, storage.ErrDataNotFound) {
-			// Не нашли, сообщаем об этом клиенту
-			log.Info("data not found", "article_id", articleId)
-			render.JSON(w, r, resp.Error("not found"))
-			return
-		}
+		// Находим статью (в кэше или в БД)
+		resData, err := dataGetter.GetData(r.Context(), articleId)
 		if err != nil {
-			// Не удалось осуществить поиск
-			log.Error("failed to get data", sl.Err(err))
-			render.JSON(w, r, resp.Error("internal error"))
+			resp.Fail(log, w, r, op, err)
 			return
 		}
 
-		log.Info("got data", slog.String("data", resData))
+		log.Info("got data", slog.Int64("article_id", resData.Id))
+
+		// Статья уже найдена, поэтому ошибка учета просмотра на ответ не влияет
+		if err := dataGetter.RecordView(r.Context(), resData.Id); err != nil {
+			log.Error("failed to record view", slog.String("op", op), sl.Err(err))
+		}
 
 		//пишем в ответ
-		w.Write([]byte("response: " + resData))
+		resp.OK(w, r, resData)
 
 		// Делаем редирект на найденный URL
 		//http.Redirect(w, r, resData, http.StatusFound)
@@ -117,72 +120,70 @@ func GetArticle(log *slog.Logger, dataGetter DataGetter) http.HandlerFunc {
 // GetTestData Получение тестовых данных с сайта https://jsonplaceholder.typicode.com
 func GetTestData(log *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		url := "https://jsonplaceholder.typicode.com/users"
-		method := "GET"
-
-		client := &http.Client{}
-		req, err := http.NewRequest(method, url, nil)
-
-		if err != nil {
-			log.Error("failed to create request", sl.Err(err))
-			return
-		}
-		res, err := client.Do(req)
-		if err != nil {
-			log.Error("failed to get data", sl.Err(err))
-			return
-		}
-		defer res.Body.Close()
+		const op = "handlers.article.GetTestData"
 
-		body, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			log.Error("failed to read data", sl.Err(err))
-			//fmt.Println(err)
-			return
-		}
-		w.Write(body)
+		proxyJSON(log, w, r, op, "https://jsonplaceholder.typicode.com/users")
 	}
 }
 
-
-
 func GetUserById(log *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.article.GetUserById"
+
 		// Роутер chi позволяет делать вот такие финты - получать GET-параметры по их именам.
 		// Имена определяются при добавлении хэндлера в роутер.
 		userId := chi.URLParam(r, "user_id")
-		if userId == "" {
-			log.Info("user_id is empty")
-			render.JSON(w, r, resp.Error("not found"))
+		if _, err := strconv.ParseInt(userId, 10, 64); err != nil {
+			log.Info("invalid user_id", slog.String("user_id", userId))
+			resp.Error(w, r, http.StatusBadRequest, "invalid user_id")
 			return
 		}
 
+		proxyJSON(log, w, r, op, "https://jsonplaceholder.typicode.com/users/"+userId)
+	}
+}
 
-		url := "https://jsonplaceholder.typicode.com/users/" + userId
-		method := "GET"
+// proxyJSON Запрашивает JSON у внешнего сервиса и отдает его клиенту в поле data.
+// Недоступность внешнего сервиса - 502, отсутствие данных у него - 404
+func proxyJSON(log *slog.Logger, w http.ResponseWriter, r *http.Request, op string, url string) {
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, url, nil)
+	if err != nil {
+		log.Error("failed to create request", slog.String("op", op), sl.Err(err))
+		resp.Error(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
 
-		client := &http.Client{}
-		req, err := http.NewRequest(method, url, nil)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Error("failed to get data", slog.String("op", op), sl.Err(err))
+		resp.Error(w, r, http.StatusBadGateway, "upstream service is unavailable")
+		return
+	}
+	defer res.Body.Close()
 
-		if err != nil {
-			log.Error("failed to create request", sl.Err(err))
-			return
-		}
-		res, err := client.Do(req)
-		if err != nil {
-			log.Error("failed to get data", sl.Err(err))
-			return
-		}
-		defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		resp.Error(w, r, http.StatusNotFound, "not found")
+		return
+	}
+	if res.StatusCode != http.StatusOK {
+		log.Error("unexpected upstream status", slog.String("op", op), slog.Int("status", res.StatusCode))
+		resp.Error(w, r, http.StatusBadGateway, "upstream service error")
+		return
+	}
 
-		body, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			log.Error("failed to read data", sl.Err(err))
-			//fmt.Println(err)
-			return
-		}
-		w.Write(body)
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		log.Error("failed to read data", slog.String("op", op), sl.Err(err))
+		resp.Error(w, r, http.StatusBadGateway, "upstream service error")
+		return
+	}
+	if !json.Valid(body) {
+		log.Error("upstream returned invalid json", slog.String("op", op))
+		resp.Error(w, r, http.StatusBadGateway, "upstream service error")
+		return
 	}
+
+	resp.OK(w, r, json.RawMessage(body))
 }
 
 //func responseOK(w http.ResponseWriter, r *http.Request, alias string) {
@@ -191,3 +192,22 @@ func GetUserById(log *slog.Logger) http.HandlerFunc {
 //		Alias:    alias,
 //	})
 //}
+
+// CacheStatsGetter is an interface for getting article cache counters.
+type CacheStatsGetter interface {
+	CacheStats() models.CacheStats
+}
+
+// CacheStatsResponse Ответ со счетчиками кэша
+type CacheStatsResponse struct {
+	Stats models.CacheStats `json:"stats"`
+}
+
+// GetCacheStats Получить счетчики кэша статей (сколько запросов к БД удалось сэкономить)
+func GetCacheStats(log *slog.Logger, statsGetter CacheStatsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp.OK(w, r, CacheStatsResponse{
+			Stats: statsGetter.CacheStats(),
+		})
+	}
+}
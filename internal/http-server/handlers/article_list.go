@@ -0,0 +1,116 @@
+//internal/http-server/handlers/article_list.go
+
+package article
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	resp "test-redis/internal/lib/api/response"
+	"test-redis/internal/models"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// ArticleLister is an interface for listing articles page by page.
+type ArticleLister interface {
+	ListArticles(ctx context.Context, q models.ArticleListQuery) (models.ArticlePage, error)
+}
+
+// ListResponse Страница списка статей
+type ListResponse struct {
+	models.ArticlePage
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// ListArticles Получить список статей.
+// Параметры: limit, offset - пагинация по номеру страницы; after_id - курсор (ид последней статьи предыдущей страницы, только при sort=id);
+// sort - id, title или rating; order - asc или desc; min_rating - минимальный рейтинг; title_prefix - начало заголовка
+func ListArticles(log *slog.Logger, articleLister ArticleLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.article.ListArticles"
+
+		q, errMsg := parseListQuery(r)
+		if errMsg != "" {
+			log.Info("invalid list query", slog.String("query", r.URL.RawQuery), slog.String("error", errMsg))
+			resp.Error(w, r, http.StatusBadRequest, errMsg)
+			return
+		}
+
+		page, err := articleLister.ListArticles(r.Context(), q)
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+
+		resp.OK(w, r, ListResponse{
+			ArticlePage: page,
+			Limit:       q.Limit,
+			Offset:      q.Offset,
+		})
+	}
+}
+
+// parseListQuery Разбор параметров запроса списка статей. Возвращает текст ошибки для клиента
+func parseListQuery(r *http.Request) (models.ArticleListQuery, string) {
+	params := r.URL.Query()
+	q := models.ArticleListQuery{Sort: models.SortById}
+
+	var err error
+	if q.Limit, err = queryInt(r, "limit", defaultListLimit); err != nil || q.Limit <= 0 || q.Limit > maxListLimit {
+		return q, "limit must be between 1 and " + strconv.Itoa(maxListLimit)
+	}
+	if q.Offset, err = queryInt(r, "offset", 0); err != nil || q.Offset < 0 {
+		return q, "offset must be a non-negative integer"
+	}
+
+	switch sort := params.Get("sort"); sort {
+	case "":
+	case models.SortById, models.SortByTitle, models.SortByRating:
+		q.Sort = sort
+	default:
+		return q, "sort must be one of: id, title, rating"
+	}
+
+	switch strings.ToLower(params.Get("order")) {
+	case "", "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		return q, "order must be asc or desc"
+	}
+
+	if value := params.Get("after_id"); value != "" {
+		if q.Sort != models.SortById {
+			return q, "after_id can be used only with sort=id"
+		}
+		if q.Offset != 0 {
+			return q, "after_id and offset can't be used together"
+		}
+		if q.AfterId, err = strconv.ParseInt(value, 10, 64); err != nil || q.AfterId <= 0 {
+			return q, "after_id must be a positive integer"
+		}
+	}
+
+	if value := params.Get("min_rating"); value != "" {
+		minRating, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return q, "min_rating must be a number"
+		}
+		q.MinRating = &minRating
+	}
+
+	q.TitlePrefix = params.Get("title_prefix")
+	if len(q.TitlePrefix) > 255 {
+		return q, "title_prefix is too long"
+	}
+
+	return q, ""
+}
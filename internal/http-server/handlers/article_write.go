@@ -0,0 +1,208 @@
+//internal/http-server/handlers/article_write.go
+
+package article
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+
+	resp "test-redis/internal/lib/api/response"
+	"test-redis/internal/lib/logger/sl"
+	"test-redis/internal/storage"
+)
+
+// ArticleSaver is an interface for creating articles.
+type ArticleSaver interface {
+	SaveArticle(ctx context.Context, title string, text string) (int64, error)
+}
+
+// ArticleUpdater is an interface for updating articles.
+type ArticleUpdater interface {
+	UpdateArticle(ctx context.Context, id int64, title *string, text *string) error
+}
+
+// ArticleDeleter is an interface for deleting articles.
+type ArticleDeleter interface {
+	DeleteArticle(ctx context.Context, id int64) error
+}
+
+// SaveRequest Тело запроса на создание (и полную замену) статьи
+type SaveRequest struct {
+	Title string `json:"title" validate:"required,max=255"`
+	Text  string `json:"text" validate:"required"`
+}
+
+// PatchRequest Тело запроса на частичное изменение статьи. Отсутствующие поля не меняются
+type PatchRequest struct {
+	Title *string `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
+	Text  *string `json:"text,omitempty" validate:"omitempty,min=1"`
+}
+
+// SaveResponse Ответ на создание статьи
+type SaveResponse struct {
+	Id int64 `json:"id,omitempty"`
+}
+
+// SaveArticle Создать статью
+func SaveArticle(log *slog.Logger, articleSaver ArticleSaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.article.SaveArticle"
+
+		var req SaveRequest
+		if !decodeRequest(log, w, r, &req) {
+			return
+		}
+
+		id, err := articleSaver.SaveArticle(r.Context(), req.Title, req.Text)
+		if errors.Is(err, storage.ErrDataExists) {
+			log.Info("article already exists", slog.String("title", req.Title))
+			resp.Error(w, r, http.StatusConflict, "article with this title already exists")
+			return
+		}
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+
+		log.Info("article saved", slog.Int64("id", id))
+
+		resp.Created(w, r, SaveResponse{Id: id})
+	}
+}
+
+// ReplaceArticle Полностью заменить статью (PUT)
+func ReplaceArticle(log *slog.Logger, articleUpdater ArticleUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.article.ReplaceArticle"
+
+		articleId, ok := parseArticleId(log, w, r)
+		if !ok {
+			return
+		}
+
+		var req SaveRequest
+		if !decodeRequest(log, w, r, &req) {
+			return
+		}
+
+		err := articleUpdater.UpdateArticle(r.Context(), articleId, &req.Title, &req.Text)
+		writeUpdateResult(log, w, r, op, articleId, err)
+	}
+}
+
+// PatchArticle Частично изменить статью (PATCH)
+func PatchArticle(log *slog.Logger, articleUpdater ArticleUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.article.PatchArticle"
+
+		articleId, ok := parseArticleId(log, w, r)
+		if !ok {
+			return
+		}
+
+		var req PatchRequest
+		if !decodeRequest(log, w, r, &req) {
+			return
+		}
+
+		if req.Title == nil && req.Text == nil {
+			log.Info("nothing to update", slog.Int64("article_id", articleId))
+			resp.Error(w, r, http.StatusBadRequest, "nothing to update")
+			return
+		}
+
+		err := articleUpdater.UpdateArticle(r.Context(), articleId, req.Title, req.Text)
+		writeUpdateResult(log, w, r, op, articleId, err)
+	}
+}
+
+// DeleteArticle Удалить статью
+func DeleteArticle(log *slog.Logger, articleDeleter ArticleDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.article.DeleteArticle"
+
+		articleId, ok := parseArticleId(log, w, r)
+		if !ok {
+			return
+		}
+
+		err := articleDeleter.DeleteArticle(r.Context(), articleId)
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+
+		log.Info("article deleted", slog.Int64("article_id", articleId))
+
+		resp.OK(w, r, nil)
+	}
+}
+
+// writeUpdateResult Общая для PUT и PATCH обработка результата изменения статьи
+func writeUpdateResult(log *slog.Logger, w http.ResponseWriter, r *http.Request, op string, articleId int64, err error) {
+	if errors.Is(err, storage.ErrDataExists) {
+		log.Info("article with this title already exists", slog.Int64("article_id", articleId))
+		resp.Error(w, r, http.StatusConflict, "article with this title already exists")
+		return
+	}
+	if err != nil {
+		resp.Fail(log, w, r, op, err)
+		return
+	}
+
+	log.Info("article updated", slog.Int64("article_id", articleId))
+
+	resp.OK(w, r, nil)
+}
+
+// parseArticleId Получить ид статьи из параметров пути. При ошибке сам пишет ответ клиенту
+func parseArticleId(log *slog.Logger, w http.ResponseWriter, r *http.Request) (int64, bool) {
+	param := chi.URLParam(r, "article_id")
+	articleId, err := strconv.ParseInt(param, 10, 64)
+	if err != nil || articleId <= 0 {
+		log.Info("invalid article_id", slog.String("article_id", param))
+		resp.Error(w, r, http.StatusBadRequest, "invalid article_id")
+		return 0, false
+	}
+
+	return articleId, true
+}
+
+// decodeRequest Декодирует JSON-тело запроса и валидирует его. При ошибке сам пишет ответ клиенту
+func decodeRequest(log *slog.Logger, w http.ResponseWriter, r *http.Request, req any) bool {
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		// Такую ошибку встретим, если получили запрос с пустым телом.
+		log.Info("request body is empty")
+		resp.Error(w, r, http.StatusBadRequest, "empty request")
+		return false
+	}
+	if err != nil {
+		log.Info("failed to decode request body", sl.Err(err))
+		resp.Error(w, r, http.StatusBadRequest, "failed to decode request")
+		return false
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		var validateErr validator.ValidationErrors
+		if !errors.As(err, &validateErr) {
+			log.Error("failed to validate request", sl.Err(err))
+			resp.Error(w, r, http.StatusBadRequest, "invalid request")
+			return false
+		}
+
+		log.Info("invalid request", sl.Err(err))
+		resp.ValidationError(w, r, validateErr)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,98 @@
+//internal/http-server/handlers/auth.go
+
+package article
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"test-redis/internal/http-server/middleware/authenticate"
+	resp "test-redis/internal/lib/api/response"
+	"test-redis/internal/lib/jwt"
+	"test-redis/internal/lib/logger/sl"
+)
+
+// CredentialsChecker is an interface for checking user credentials.
+type CredentialsChecker interface {
+	Check(user string, password string) bool
+}
+
+// TokenIssuer is an interface for issuing access tokens.
+type TokenIssuer interface {
+	Issue(user string) (string, jwt.Claims, error)
+}
+
+// TokenRevoker is an interface for revoking access tokens.
+type TokenRevoker interface {
+	Revoke(ctx context.Context, claims jwt.Claims) error
+}
+
+// LoginRequest Тело запроса на вход
+type LoginRequest struct {
+	User     string `json:"user" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// LoginResponse Выданный токен
+type LoginResponse struct {
+	Token     string `json:"token"`
+	TokenType string `json:"token_type"`
+	ExpiresAt int64  `json:"expires_at"` // Время окончания действия токена (Unix)
+}
+
+// Login Вход по имени пользователя и паролю, в ответе - JWT-токен для заголовка Authorization: Bearer <token>
+func Login(log *slog.Logger, checker CredentialsChecker, issuer TokenIssuer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.article.Login"
+
+		var req LoginRequest
+		if !decodeRequest(log, w, r, &req) {
+			return
+		}
+
+		if !checker.Check(req.User, req.Password) {
+			log.Info("invalid credentials", slog.String("user", req.User))
+			resp.Error(w, r, http.StatusUnauthorized, "invalid user or password")
+			return
+		}
+
+		token, claims, err := issuer.Issue(req.User)
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+
+		log.Info("user logged in", slog.String("user", claims.Subject))
+
+		resp.OK(w, r, LoginResponse{
+			Token:     token,
+			TokenType: "Bearer",
+			ExpiresAt: claims.ExpiresAt,
+		})
+	}
+}
+
+// Logout Выход: токен запроса отзывается и больше не принимается. Требует middleware authenticate
+func Logout(log *slog.Logger, revoker TokenRevoker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.article.Logout"
+
+		claims, ok := authenticate.ClaimsFromContext(r.Context())
+		if !ok {
+			log.Error("no token claims in context", slog.String("op", op))
+			resp.Error(w, r, http.StatusUnauthorized, "authorization required")
+			return
+		}
+
+		if err := revoker.Revoke(r.Context(), claims); err != nil {
+			log.Error("failed to revoke token", slog.String("op", op), sl.Err(err))
+			resp.Error(w, r, http.StatusInternalServerError, "internal error")
+			return
+		}
+
+		log.Info("user logged out", slog.String("user", claims.Subject))
+
+		resp.OK(w, r, nil)
+	}
+}
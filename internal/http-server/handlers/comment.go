@@ -0,0 +1,165 @@
+//internal/http-server/handlers/comment.go
+
+package article
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	resp "test-redis/internal/lib/api/response"
+	"test-redis/internal/models"
+)
+
+const (
+	defaultCommentsLimit = 20
+	maxCommentsLimit     = 100
+)
+
+// CommentGetter is an interface for getting article comments page by page.
+type CommentGetter interface {
+	GetComments(ctx context.Context, articleId int64, limit int, offset int) ([]models.Comment, int, error)
+}
+
+// CommentSaver is an interface for adding comments.
+type CommentSaver interface {
+	SaveComment(ctx context.Context, articleId int64, text string, score *float64) (int64, error)
+}
+
+// CommentDeleter is an interface for deleting comments.
+type CommentDeleter interface {
+	DeleteComment(ctx context.Context, articleId int64, commentId int64) error
+}
+
+// CommentRequest Тело запроса на добавление комментария
+type CommentRequest struct {
+	Text  string   `json:"text" validate:"required,max=4096"`
+	Score *float64 `json:"score" validate:"required,gte=0,lte=100"`
+}
+
+// CommentsResponse Страница комментариев к статье
+type CommentsResponse struct {
+	Comments []models.Comment `json:"comments"`
+	Total    int              `json:"total"`
+	Limit    int              `json:"limit"`
+	Offset   int              `json:"offset"`
+}
+
+// GetComments Получить комментарии к статье (параметры пагинации: limit, offset)
+func GetComments(log *slog.Logger, commentGetter CommentGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.article.GetComments"
+
+		articleId, ok := parseArticleId(log, w, r)
+		if !ok {
+			return
+		}
+
+		limit, err := queryInt(r, "limit", defaultCommentsLimit)
+		if err != nil || limit <= 0 || limit > maxCommentsLimit {
+			log.Info("invalid limit", slog.String("limit", r.URL.Query().Get("limit")))
+			resp.Error(w, r, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxCommentsLimit))
+			return
+		}
+
+		offset, err := queryInt(r, "offset", 0)
+		if err != nil || offset < 0 {
+			log.Info("invalid offset", slog.String("offset", r.URL.Query().Get("offset")))
+			resp.Error(w, r, http.StatusBadRequest, "offset must be a non-negative integer")
+			return
+		}
+
+		comments, total, err := commentGetter.GetComments(r.Context(), articleId, limit, offset)
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+
+		resp.OK(w, r, CommentsResponse{
+			Comments: comments,
+			Total:    total,
+			Limit:    limit,
+			Offset:   offset,
+		})
+	}
+}
+
+// SaveComment Добавить комментарий к статье
+func SaveComment(log *slog.Logger, commentSaver CommentSaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.article.SaveComment"
+
+		articleId, ok := parseArticleId(log, w, r)
+		if !ok {
+			return
+		}
+
+		var req CommentRequest
+		if !decodeRequest(log, w, r, &req) {
+			return
+		}
+
+		id, err := commentSaver.SaveComment(r.Context(), articleId, req.Text, req.Score)
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+
+		log.Info("comment saved", slog.Int64("article_id", articleId), slog.Int64("id", id))
+
+		resp.Created(w, r, SaveResponse{Id: id})
+	}
+}
+
+// DeleteComment Удалить комментарий к статье
+func DeleteComment(log *slog.Logger, commentDeleter CommentDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.article.DeleteComment"
+
+		articleId, ok := parseArticleId(log, w, r)
+		if !ok {
+			return
+		}
+
+		param := chi.URLParam(r, "comment_id")
+		commentId, err := strconv.ParseInt(param, 10, 64)
+		if err != nil || commentId <= 0 {
+			log.Info("invalid comment_id", slog.String("comment_id", param))
+			resp.Error(w, r, http.StatusBadRequest, "invalid comment_id")
+			return
+		}
+
+		err = commentDeleter.DeleteComment(r.Context(), articleId, commentId)
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+
+		log.Info("comment deleted", slog.Int64("article_id", articleId), slog.Int64("comment_id", commentId))
+
+		resp.OK(w, r, nil)
+	}
+}
+
+// queryInt Получить целочисленный параметр запроса; если он не задан - значение по умолчанию
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+// queryBool Получить логический параметр запроса; если он не задан - false
+func queryBool(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(value)
+}
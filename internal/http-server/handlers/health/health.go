@@ -0,0 +1,137 @@
+//internal/http-server/handlers/health/health.go
+
+package health
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	resp "test-redis/internal/lib/api/response"
+)
+
+// checkTimeout Максимальное время проверки одного компонента
+const checkTimeout = 2 * time.Second
+
+const (
+	StatusDegraded = "Degraded" // Некритичный компонент недоступен, сервис работает с ограничениями
+
+	componentUp   = "up"
+	componentDown = "down"
+)
+
+// Component Проверяемый компонент сервиса (БД, кэш)
+type Component struct {
+	Name     string
+	Check    func(ctx context.Context) error
+	Critical bool // Недоступность критичного компонента делает сервис неготовым (503)
+}
+
+// ComponentStatus Результат проверки компонента
+type ComponentStatus struct {
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+	Latency  string `json:"latency"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Response Состояние компонентов (поле data ответа)
+type Response struct {
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// Liveness Проверка "живости" (/healthz): процесс отвечает - значит, жив, код всегда 200.
+// Состояние компонентов выводится для информации
+func Liveness(log *slog.Logger, components ...Component) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		statuses, _, _ := checkAll(r.Context(), components)
+
+		resp.OK(w, r, Response{Components: statuses})
+	}
+}
+
+// Readiness Проверка готовности (/readyz): 503, если недоступен хотя бы один критичный компонент.
+// Если недоступны только некритичные - 200 со статусом Degraded
+func Readiness(log *slog.Logger, components ...Component) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		statuses, criticalDown, degraded := checkAll(r.Context(), components)
+
+		// Состояние компонентов отдается и при ошибке, поэтому ответ собирается здесь, а не через resp.Error
+		response := resp.Response{
+			Status:    resp.StatusOK,
+			RequestID: middleware.GetReqID(r.Context()),
+			Data:      Response{Components: statuses},
+		}
+
+		switch {
+		case criticalDown:
+			log.Warn("service is not ready", slog.Any("components", statuses))
+			response.Status = resp.StatusError
+			response.Code = resp.CodeUnavailable
+			response.Message = "critical component unavailable"
+			render.Status(r, http.StatusServiceUnavailable)
+		case degraded:
+			response.Status = StatusDegraded
+		}
+
+		render.JSON(w, r, response)
+	}
+}
+
+// checkAll Параллельно проверяет все компоненты
+func checkAll(ctx context.Context, components []Component) (statuses map[string]ComponentStatus, criticalDown bool, degraded bool) {
+	statuses = make(map[string]ComponentStatus, len(components))
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, c := range components {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			status := check(ctx, c)
+
+			mu.Lock()
+			defer mu.Unlock()
+			statuses[c.Name] = status
+			if status.Status == componentDown {
+				if c.Critical {
+					criticalDown = true
+				} else {
+					degraded = true
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return statuses, criticalDown, degraded
+}
+
+// check Проверяет компонент и замеряет время ответа
+func check(ctx context.Context, c Component) ComponentStatus {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Check(ctx)
+
+	status := ComponentStatus{
+		Status:   componentUp,
+		Critical: c.Critical,
+		Latency:  time.Since(start).String(),
+	}
+	if err != nil {
+		status.Status = componentDown
+		status.Error = err.Error()
+	}
+
+	return status
+}
@@ -0,0 +1,97 @@
+//internal/http-server/handlers/leaderboard.go
+
+package article
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	resp "test-redis/internal/lib/api/response"
+	"test-redis/internal/models"
+)
+
+const (
+	defaultBoardLimit = 10
+	maxBoardLimit     = 100
+)
+
+// TrendingGetter is an interface for getting trending articles.
+type TrendingGetter interface {
+	GetTrending(ctx context.Context, limit int) ([]models.RankedArticle, error)
+}
+
+// TopGetter is an interface for getting top rated articles.
+type TopGetter interface {
+	GetTop(ctx context.Context, window string, limit int) ([]models.RankedArticle, error)
+}
+
+// BoardResponse Рейтинг статей
+type BoardResponse struct {
+	Window   string                 `json:"window,omitempty"`
+	Articles []models.RankedArticle `json:"articles"`
+}
+
+// GetTrending Самые обсуждаемые и просматриваемые статьи (активность за последние дни с затуханием). Параметр: limit
+func GetTrending(log *slog.Logger, trendingGetter TrendingGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.article.GetTrending"
+
+		limit, ok := parseBoardLimit(log, w, r)
+		if !ok {
+			return
+		}
+
+		articles, err := trendingGetter.GetTrending(r.Context(), limit)
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+
+		resp.OK(w, r, BoardResponse{Articles: articles})
+	}
+}
+
+// GetTop Статьи с наибольшим средним рейтингом. Параметры: window - day, week или all (по умолчанию), limit
+func GetTop(log *slog.Logger, topGetter TopGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.article.GetTop"
+
+		window := r.URL.Query().Get("window")
+		switch window {
+		case "":
+			window = models.WindowAll
+		case models.WindowDay, models.WindowWeek, models.WindowAll:
+		default:
+			log.Info("invalid window", slog.String("window", window))
+			resp.Error(w, r, http.StatusBadRequest, "window must be one of: day, week, all")
+			return
+		}
+
+		limit, ok := parseBoardLimit(log, w, r)
+		if !ok {
+			return
+		}
+
+		articles, err := topGetter.GetTop(r.Context(), window, limit)
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+
+		resp.OK(w, r, BoardResponse{Window: window, Articles: articles})
+	}
+}
+
+// parseBoardLimit Разбор параметра limit. При ошибке сам пишет ответ 400 и возвращает false
+func parseBoardLimit(log *slog.Logger, w http.ResponseWriter, r *http.Request) (int, bool) {
+	limit, err := queryInt(r, "limit", defaultBoardLimit)
+	if err != nil || limit <= 0 || limit > maxBoardLimit {
+		log.Info("invalid limit", slog.String("limit", r.URL.Query().Get("limit")))
+		resp.Error(w, r, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxBoardLimit))
+		return 0, false
+	}
+
+	return limit, true
+}
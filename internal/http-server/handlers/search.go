@@ -0,0 +1,74 @@
+//internal/http-server/handlers/search.go
+
+package article
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+
+	resp "test-redis/internal/lib/api/response"
+	"test-redis/internal/models"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+	maxSearchQueryLen  = 256
+)
+
+// ArticleSearcher is an interface for full-text search over articles.
+type ArticleSearcher interface {
+	Search(ctx context.Context, query string, limit int, offset int) (models.SearchPage, error)
+}
+
+// SearchResponse Страница результатов поиска
+type SearchResponse struct {
+	models.SearchPage
+	Query  string `json:"query"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+// Search Полнотекстовый поиск статей. Параметры: q - запрос, limit, offset - пагинация
+func Search(log *slog.Logger, articleSearcher ArticleSearcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.article.Search"
+
+		query := r.URL.Query().Get("q")
+		if query == "" || utf8.RuneCountInString(query) > maxSearchQueryLen {
+			log.Info("invalid search query", slog.Int("length", len(query)))
+			resp.Error(w, r, http.StatusBadRequest, "q must be between 1 and "+strconv.Itoa(maxSearchQueryLen)+" characters")
+			return
+		}
+
+		limit, err := queryInt(r, "limit", defaultSearchLimit)
+		if err != nil || limit <= 0 || limit > maxSearchLimit {
+			log.Info("invalid limit", slog.String("limit", r.URL.Query().Get("limit")))
+			resp.Error(w, r, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxSearchLimit))
+			return
+		}
+
+		offset, err := queryInt(r, "offset", 0)
+		if err != nil || offset < 0 {
+			log.Info("invalid offset", slog.String("offset", r.URL.Query().Get("offset")))
+			resp.Error(w, r, http.StatusBadRequest, "offset must be a non-negative integer")
+			return
+		}
+
+		page, err := articleSearcher.Search(r.Context(), query, limit, offset)
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+
+		resp.OK(w, r, SearchResponse{
+			SearchPage: page,
+			Query:      query,
+			Limit:      limit,
+			Offset:     offset,
+		})
+	}
+}
@@ -0,0 +1,42 @@
+//internal/http-server/handlers/views.go
+
+package article
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	resp "test-redis/internal/lib/api/response"
+)
+
+// ViewsGetter is an interface for getting article view counts.
+type ViewsGetter interface {
+	GetViews(ctx context.Context, articleId int64) (int64, error)
+}
+
+// ViewsResponse Количество просмотров статьи
+type ViewsResponse struct {
+	ArticleId int64 `json:"article_id"`
+	Views     int64 `json:"views"`
+}
+
+// GetViews Получить количество просмотров статьи (включая еще не перенесенные в БД)
+func GetViews(log *slog.Logger, viewsGetter ViewsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.article.GetViews"
+
+		articleId, ok := parseArticleId(log, w, r)
+		if !ok {
+			return
+		}
+
+		views, err := viewsGetter.GetViews(r.Context(), articleId)
+		if err != nil {
+			resp.Fail(log, w, r, op, err)
+			return
+		}
+
+		resp.OK(w, r, ViewsResponse{ArticleId: articleId, Views: views})
+	}
+}
@@ -0,0 +1,80 @@
+// internal/http-server/middleware/authenticate/authenticate.go
+
+// middleware для проверки JWT-токена (заголовок Authorization: Bearer <token>)
+package authenticate
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"test-redis/internal/auth"
+	resp "test-redis/internal/lib/api/response"
+	"test-redis/internal/lib/jwt"
+	"test-redis/internal/lib/logger/sl"
+)
+
+// TokenVerifier is an interface for verifying access tokens.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (jwt.Claims, error)
+}
+
+type ctxKey struct{}
+
+func New(log *slog.Logger, verifier TokenVerifier) func(next http.Handler) http.Handler {
+	log = log.With(
+		slog.String("component", "middleware/authenticate"),
+	)
+
+	log.Info("auth middleware enabled")
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			token := BearerToken(r)
+			if token == "" {
+				unauthorized(w, r, "authorization required")
+				return
+			}
+
+			claims, err := verifier.Verify(r.Context(), token)
+			if errors.Is(err, auth.ErrInvalidToken) {
+				log.Info("invalid token", sl.Err(err))
+				unauthorized(w, r, "invalid token")
+				return
+			}
+			if err != nil {
+				// Не удалось проверить отзыв токена (кэш недоступен) - пропускать запрос нельзя
+				log.Error("failed to verify token", sl.Err(err))
+				resp.Error(w, r, http.StatusServiceUnavailable, "authentication is temporarily unavailable")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey{}, claims)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// ClaimsFromContext Поля токена аутентифицированного запроса
+func ClaimsFromContext(ctx context.Context) (jwt.Claims, bool) {
+	claims, ok := ctx.Value(ctxKey{}).(jwt.Claims)
+	return claims, ok
+}
+
+// BearerToken Токен из заголовка Authorization ("" - заголовка нет или в нем не Bearer-токен)
+func BearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, msg string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="test-redis"`)
+	resp.Error(w, r, http.StatusUnauthorized, msg)
+}
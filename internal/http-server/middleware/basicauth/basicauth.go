@@ -0,0 +1,41 @@
+// internal/http-server/middleware/basicauth/basicauth.go
+
+// middleware для HTTP Basic-аутентификации (служебные маршруты)
+package basicauth
+
+import (
+	"log/slog"
+	"net/http"
+
+	resp "test-redis/internal/lib/api/response"
+)
+
+// CredentialsChecker is an interface for checking user credentials.
+// Реализация должна сравнивать учетные данные за постоянное время
+type CredentialsChecker interface {
+	Check(user string, password string) bool
+}
+
+func New(log *slog.Logger, realm string, checker CredentialsChecker) func(next http.Handler) http.Handler {
+	log = log.With(
+		slog.String("component", "middleware/basicauth"),
+	)
+
+	log.Info("basic auth middleware enabled", slog.String("realm", realm))
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			user, password, ok := r.BasicAuth()
+			if !ok || !checker.Check(user, password) {
+				log.Info("unauthorized request", slog.String("path", r.URL.Path), slog.String("user", user))
+				w.Header().Set("WWW-Authenticate", `Basic realm="`+realm+`", charset="UTF-8"`)
+				resp.Error(w, r, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,53 @@
+// internal/http-server/middleware/cors/cors.go
+
+// middleware CORS (github.com/go-chi/cors), в котором разрешенные источники можно заменить на ходу
+package cors
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-chi/cors"
+)
+
+// CORS middleware CORS. Разрешенные источники заменяются методом SetAllowedOrigins
+// (перечитывание конфигурации), остальные параметры задаются при создании
+type CORS struct {
+	log     *slog.Logger
+	opts    cors.Options
+	current atomic.Pointer[cors.Cors]
+}
+
+func New(log *slog.Logger, opts cors.Options) *CORS {
+	c := &CORS{
+		log: log.With(
+			slog.String("component", "middleware/cors"),
+		),
+		opts: opts,
+	}
+
+	c.current.Store(cors.New(opts))
+	c.log.Info("cors middleware enabled", slog.String("allowed_origins", strings.Join(opts.AllowedOrigins, ",")))
+
+	return c
+}
+
+// SetAllowedOrigins Замена разрешенных источников запросов
+func (c *CORS) SetAllowedOrigins(origins []string) {
+	opts := c.opts
+	opts.AllowedOrigins = origins
+
+	c.current.Store(cors.New(opts))
+	c.log.Info("cors allowed origins updated", slog.String("allowed_origins", strings.Join(origins, ",")))
+}
+
+// Handler middleware CORS
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		c.current.Load().Handler(next).ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(fn)
+}
@@ -0,0 +1,49 @@
+// internal/http-server/middleware/metrics/metrics.go
+
+// middleware для сбора метрик HTTP-запросов (количество и время обработки)
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"test-redis/internal/lib/metrics"
+)
+
+// unmatchedRoute Значение метки route для запросов, не попавших ни в один маршрут.
+// Сам путь в метку не пишем, иначе количество временных рядов будет неограниченным
+const unmatchedRoute = "unmatched"
+
+func New() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			start := time.Now()
+
+			next.ServeHTTP(ww, r)
+
+			// Шаблон маршрута (например, /article/{article_id}) известен только после маршрутизации
+			route := unmatchedRoute
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				if pattern := rctx.RoutePattern(); pattern != "" {
+					route = pattern
+				}
+			}
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			statusLabel := strconv.Itoa(status)
+
+			metrics.HTTPRequestsTotal.Inc(r.Method, route, statusLabel)
+			metrics.HTTPRequestDuration.Observe(time.Since(start).Seconds(), r.Method, route, statusLabel)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,199 @@
+// internal/http-server/middleware/ratelimit/ratelimit.go
+
+// middleware для ограничения частоты запросов (скользящее окно, счетчики хранятся в кэше)
+package ratelimit
+
+import (
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"test-redis/internal/cache"
+	resp "test-redis/internal/lib/api/response"
+	"test-redis/internal/lib/logger/sl"
+)
+
+// Признаки, по которым запросы объединяются в один счетчик
+const (
+	KeyByIP    = "ip"    // Адрес клиента
+	KeyByUser  = "user"  // Аутентифицированный пользователь (для анонимных запросов - адрес клиента)
+	KeyByRoute = "route" // Шаблон маршрута (например, GET /article/{article_id})
+)
+
+// keyPrefix Пространство имен ключей ограничителя в кэше
+const keyPrefix = "ratelimit:"
+
+// Limit Ограничение: не больше Requests запросов за Window
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// Options Параметры ограничения частоты запросов
+type Options struct {
+	Disabled bool // Ограничение выключено: запросы пропускаются без проверки
+	Default  Limit
+	KeyBy    []string         // Комбинация признаков KeyByIP, KeyByUser, KeyByRoute
+	Routes   map[string]Limit // Отдельные ограничения для маршрутов ("POST /articles"); у каждого маршрута свой счетчик
+	// User Имя аутентифицированного пользователя запроса ("" - анонимный запрос).
+	// По умолчанию - имя пользователя из заголовка Basic-аутентификации
+	User func(r *http.Request) string
+}
+
+// Limiter middleware ограничения частоты запросов. Параметры можно заменить на ходу (Update),
+// например при перечитывании конфигурации; счетчики при этом сохраняются
+type Limiter struct {
+	log     *slog.Logger
+	limiter cache.RateLimiter
+	opts    atomic.Pointer[Options]
+}
+
+func New(log *slog.Logger, limiter cache.RateLimiter, opts Options) *Limiter {
+	l := &Limiter{
+		log: log.With(
+			slog.String("component", "middleware/ratelimit"),
+		),
+		limiter: limiter,
+	}
+
+	l.store(opts)
+	l.log.Info("rate limit middleware enabled", optionsAttrs(opts)...)
+
+	return l
+}
+
+// Update Замена параметров ограничения
+func (l *Limiter) Update(opts Options) {
+	l.store(opts)
+	l.log.Info("rate limit options updated", optionsAttrs(opts)...)
+}
+
+// store Сохранение параметров с заполнением значений по умолчанию
+func (l *Limiter) store(opts Options) {
+	if opts.User == nil {
+		opts.User = basicAuthUser
+	}
+
+	l.opts.Store(&opts)
+}
+
+// Handler middleware ограничения частоты запросов
+func (l *Limiter) Handler(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		opts := l.opts.Load()
+		if opts.Disabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		route := routePattern(r)
+
+		limit, ok := opts.Routes[route]
+		if !ok {
+			limit = opts.Default
+		}
+
+		res, err := l.limiter.Allow(r.Context(), key(r, route, ok, opts), limit.Requests, limit.Window)
+		if err != nil {
+			// Кэш недоступен - пропускаем запрос: лучше временно остаться без ограничения, чем отказывать всем
+			l.log.Error("failed to check rate limit", sl.Err(err))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		w.Header().Set("X-RateLimit-Reset", seconds(res.ResetAfter))
+
+		if !res.Allowed {
+			w.Header().Set("Retry-After", seconds(res.RetryAfter))
+			resp.Error(w, r, http.StatusTooManyRequests, "too many requests")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// optionsAttrs Параметры ограничения для лога
+func optionsAttrs(opts Options) []any {
+	return []any{
+		slog.Bool("disabled", opts.Disabled),
+		slog.Int("requests", opts.Default.Requests),
+		slog.String("window", opts.Default.Window.String()),
+		slog.String("key_by", strings.Join(opts.KeyBy, ",")),
+		slog.Int("routes", len(opts.Routes)),
+	}
+}
+
+// key Ключ счетчика запроса. Для маршрута с собственным ограничением маршрут входит в ключ всегда
+func key(r *http.Request, route string, routeLimit bool, opts *Options) string {
+	parts := make([]string, 0, len(opts.KeyBy)+1)
+	for _, by := range opts.KeyBy {
+		switch by {
+		case KeyByIP:
+			parts = append(parts, "ip="+clientIP(r))
+		case KeyByUser:
+			if user := opts.User(r); user != "" {
+				parts = append(parts, "user="+user)
+			} else {
+				parts = append(parts, "ip="+clientIP(r))
+			}
+		case KeyByRoute:
+			routeLimit = true
+		}
+	}
+	if routeLimit {
+		parts = append(parts, "route="+route)
+	}
+
+	return keyPrefix + strings.Join(parts, ":")
+}
+
+// routePattern Метод и шаблон маршрута запроса ("GET /article/{article_id}").
+// middleware выполняется до маршрутизации, поэтому маршрут ищется в роутере отдельно
+func routePattern(r *http.Request) string {
+	pattern := "unmatched"
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.Routes != nil {
+		tctx := chi.NewRouteContext()
+		if rctx.Routes.Match(tctx, r.Method, r.URL.Path) {
+			pattern = tctx.RoutePattern()
+		}
+	}
+
+	return r.Method + " " + pattern
+}
+
+// clientIP Адрес клиента (без порта)
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// basicAuthUser Имя пользователя из заголовка Basic-аутентификации
+func basicAuthUser(r *http.Request) string {
+	user, _, ok := r.BasicAuth()
+	if !ok {
+		return ""
+	}
+
+	return user
+}
+
+// seconds Длительность в целых секундах с округлением вверх (формат Retry-After)
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,47 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"test-redis/internal/cache"
+	"test-redis/internal/lib/logger/sl"
+	"test-redis/internal/storage"
+)
+
+// FromError Сопоставление ошибок хранилища и кэша с ответом клиенту: HTTP-статус, код и описание ошибки.
+// Подробности неизвестных ошибок клиенту не показываются (они есть только в логах)
+func FromError(err error) (status int, code string, msg string) {
+	switch {
+	case errors.Is(err, storage.ErrDataNotFound), errors.Is(err, cache.ErrDataNotFound):
+		return http.StatusNotFound, CodeNotFound, "not found"
+	case errors.Is(err, storage.ErrDataExists):
+		return http.StatusConflict, CodeConflict, "already exists"
+	case errors.Is(err, storage.ErrEmptyQuery):
+		return http.StatusBadRequest, CodeBadRequest, "q must contain at least one word"
+	case errors.Is(err, storage.ErrSchemaOutdated):
+		return http.StatusServiceUnavailable, CodeUnavailable, "service unavailable"
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, CodeTimeout, "request timed out"
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest, CodeCanceled, "request canceled"
+	default:
+		return http.StatusInternalServerError, CodeInternal, "internal error"
+	}
+}
+
+// Fail Ответ по ошибке хранилища или кэша (см. FromError). Ошибка пишется в лог:
+// ошибки сервиса (5xx) - на уровне Error, ожидаемые ситуации (не найдено, конфликт, клиент отключился) - на уровне Info
+func Fail(log *slog.Logger, w http.ResponseWriter, r *http.Request, op string, err error) {
+	status, code, msg := FromError(err)
+
+	level := slog.LevelInfo
+	if status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	log.Log(r.Context(), level, "request failed", slog.String("op", op), slog.Int("status", status), sl.Err(err))
+
+	ErrorCode(w, r, status, code, msg)
+}
@@ -0,0 +1,48 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"test-redis/internal/cache"
+	"test-redis/internal/storage"
+)
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "storage not found", err: storage.ErrDataNotFound, wantStatus: http.StatusNotFound, wantCode: CodeNotFound},
+		{name: "cache not found", err: cache.ErrDataNotFound, wantStatus: http.StatusNotFound, wantCode: CodeNotFound},
+		{name: "wrapped not found", err: fmt.Errorf("storage.sqlite.GetData: %w", storage.ErrDataNotFound), wantStatus: http.StatusNotFound, wantCode: CodeNotFound},
+		{name: "exists", err: storage.ErrDataExists, wantStatus: http.StatusConflict, wantCode: CodeConflict},
+		{name: "empty query", err: storage.ErrEmptyQuery, wantStatus: http.StatusBadRequest, wantCode: CodeBadRequest},
+		{name: "schema outdated", err: storage.ErrSchemaOutdated, wantStatus: http.StatusServiceUnavailable, wantCode: CodeUnavailable},
+		{name: "timeout", err: fmt.Errorf("select: %w", context.DeadlineExceeded), wantStatus: http.StatusGatewayTimeout, wantCode: CodeTimeout},
+		{name: "canceled", err: context.Canceled, wantStatus: StatusClientClosedRequest, wantCode: CodeCanceled},
+		{name: "unknown", err: errors.New("disk I/O error"), wantStatus: http.StatusInternalServerError, wantCode: CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, code, msg := FromError(tt.err)
+			if status != tt.wantStatus || code != tt.wantCode {
+				t.Errorf("FromError(%v) = %d, %q, want %d, %q", tt.err, status, code, tt.wantStatus, tt.wantCode)
+			}
+			if msg == "" {
+				t.Errorf("FromError(%v) msg is empty", tt.err)
+			}
+			// Подробности ошибки клиенту не показываются
+			if strings.Contains(msg, tt.err.Error()) {
+				t.Errorf("FromError(%v) msg = %q leaks error details", tt.err, msg)
+			}
+		})
+	}
+}
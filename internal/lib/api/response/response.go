@@ -2,13 +2,24 @@ package response
 
 import (
 	"fmt"
-	"github.com/go-playground/validator/v10"
+	"net/http"
+	"reflect"
 	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
 )
 
+// Response Единый формат ответов API.
+// Успешный ответ: {"status":"OK","request_id":"...","data":{...}},
+// ошибка: {"status":"Error","code":"not_found","message":"not found","request_id":"..."}
 type Response struct {
-	Status string `json:"status"`
-	Error  string `json:"error,omitempty"`
+	Status    string `json:"status"`
+	Code      string `json:"code,omitempty"`       // Код ошибки для программной обработки (см. Code*)
+	Message   string `json:"message,omitempty"`    // Описание ошибки
+	RequestID string `json:"request_id,omitempty"` // Ид запроса, по нему запрос можно найти в логах
+	Data      any    `json:"data,omitempty"`
 }
 
 const (
@@ -16,20 +27,85 @@ const (
 	StatusError = "Error"
 )
 
-func Error(msg string) Response {
-	return Response{
-		Status: StatusError,
-		Error:  msg,
-	}
+// Коды ошибок
+const (
+	CodeBadRequest       = "bad_request"
+	CodeValidation       = "validation_failed"
+	CodeUnauthorized     = "unauthorized"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeConflict         = "conflict"
+	CodeTooManyRequests  = "too_many_requests"
+	CodeCanceled         = "canceled"
+	CodeInternal         = "internal_error"
+	CodeNotImplemented   = "not_implemented"
+	CodeBadGateway       = "bad_gateway"
+	CodeUnavailable      = "unavailable"
+	CodeTimeout          = "timeout"
+)
+
+// StatusClientClosedRequest Клиент закрыл соединение, не дождавшись ответа (нестандартный код, как в nginx)
+const StatusClientClosedRequest = 499
+
+// codeByStatus Код ошибки по умолчанию для HTTP-статуса
+var codeByStatus = map[int]string{
+	http.StatusBadRequest:          CodeBadRequest,
+	http.StatusUnauthorized:        CodeUnauthorized,
+	http.StatusNotFound:            CodeNotFound,
+	http.StatusMethodNotAllowed:    CodeMethodNotAllowed,
+	http.StatusConflict:            CodeConflict,
+	http.StatusTooManyRequests:     CodeTooManyRequests,
+	StatusClientClosedRequest:      CodeCanceled,
+	http.StatusInternalServerError: CodeInternal,
+	http.StatusNotImplemented:      CodeNotImplemented,
+	http.StatusBadGateway:          CodeBadGateway,
+	http.StatusServiceUnavailable:  CodeUnavailable,
+	http.StatusGatewayTimeout:      CodeTimeout,
+}
+
+// JSON Успешный ответ с HTTP-статусом status. data == nil - ответ без данных
+func JSON(w http.ResponseWriter, r *http.Request, status int, data any) {
+	render.Status(r, status)
+	render.JSON(w, r, Response{
+		Status:    StatusOK,
+		RequestID: middleware.GetReqID(r.Context()),
+		Data:      data,
+	})
 }
 
-func OK() Response {
-	return Response{
-		Status: StatusOK,
+// OK Успешный ответ (200)
+func OK(w http.ResponseWriter, r *http.Request, data any) {
+	JSON(w, r, http.StatusOK, data)
+}
+
+// Created Ответ на создание объекта (201)
+func Created(w http.ResponseWriter, r *http.Request, data any) {
+	JSON(w, r, http.StatusCreated, data)
+}
+
+// Error Ответ с ошибкой. Код ошибки определяется по HTTP-статусу
+func Error(w http.ResponseWriter, r *http.Request, status int, msg string) {
+	code, ok := codeByStatus[status]
+	if !ok {
+		code = CodeInternal
 	}
+
+	ErrorCode(w, r, status, code, msg)
 }
 
-func ValidationError(errs validator.ValidationErrors) Response {
+// ErrorCode Ответ с ошибкой и явно заданным кодом
+func ErrorCode(w http.ResponseWriter, r *http.Request, status int, code string, msg string) {
+	render.Status(r, status)
+	render.JSON(w, r, Response{
+		Status:    StatusError,
+		Code:      code,
+		Message:   msg,
+		RequestID: middleware.GetReqID(r.Context()),
+	})
+}
+
+// ValidationError Ответ 400 с описанием всех ошибок валидации тела запроса
+func ValidationError(w http.ResponseWriter, r *http.Request, errs validator.ValidationErrors) {
 	var errMsgs []string
 
 	for _, err := range errs {
@@ -38,14 +114,32 @@ func ValidationError(errs validator.ValidationErrors) Response {
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is a required field", err.Field()))
 		case "url":
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is not a valid url", err.Field()))
+		case "min":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be at least %s", err.Field(), limitParam(err)))
+		case "max":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be at most %s", err.Field(), limitParam(err)))
+		case "gte":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be greater than or equal to %s", err.Field(), err.Param()))
+		case "lte":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be less than or equal to %s", err.Field(), err.Param()))
 		default:
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is not valid", err.Field()))
 
 		}
 	}
 
-	return Response{
-		Status: StatusError,
-		Error:  strings.Join(errMsgs, ", "),
+	ErrorCode(w, r, http.StatusBadRequest, CodeValidation, strings.Join(errMsgs, ", "))
+}
+
+// limitParam Граница правил min/max с единицами измерения: для строк ограничивается длина, для списков - количество
+// элементов, для чисел - само значение
+func limitParam(err validator.FieldError) string {
+	switch err.Kind() {
+	case reflect.String:
+		return err.Param() + " characters long"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return err.Param() + " items"
+	default:
+		return err.Param()
 	}
 }
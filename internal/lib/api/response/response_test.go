@@ -0,0 +1,61 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestValidationError(t *testing.T) {
+	type request struct {
+		Title string   `validate:"min=3,max=5"`
+		Tags  []string `validate:"max=2"`
+		Limit int      `validate:"min=1,max=50"`
+		Score *float64 `validate:"omitempty,max=10"`
+	}
+
+	score := 11.5
+	tests := []struct {
+		name string
+		req  request
+		want []string
+	}{
+		{name: "string length", req: request{Title: "ab", Limit: 1}, want: []string{"field Title must be at least 3 characters long"}},
+		{name: "slice items", req: request{Title: "abc", Tags: []string{"a", "b", "c"}, Limit: 1}, want: []string{"field Tags must be at most 2 items"}},
+		{name: "number value", req: request{Title: "abc", Limit: 0}, want: []string{"field Limit must be at least 1"}},
+		{name: "pointer to number", req: request{Title: "abc", Limit: 1, Score: &score}, want: []string{"field Score must be at most 10"}},
+		{
+			name: "all together",
+			req:  request{Title: "abcdef", Limit: 51},
+			want: []string{"field Title must be at most 5 characters long", "field Limit must be at most 50"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs validator.ValidationErrors
+			if !errors.As(validator.New().Struct(tt.req), &errs) {
+				t.Fatal("validation passed, want errors")
+			}
+
+			w := httptest.NewRecorder()
+			ValidationError(w, httptest.NewRequest(http.MethodPost, "/", nil), errs)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+			var resp Response
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := resp.Message, strings.Join(tt.want, ", "); got != want {
+				t.Errorf("message = %q, want %q", got, want)
+			}
+		})
+	}
+}
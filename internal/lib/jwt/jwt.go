@@ -0,0 +1,123 @@
+// internal/lib/jwt/jwt.go
+
+// Минимальная реализация JWT (RFC 7519) с подписью HS256: только то, что нужно сервису
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMalformed = errors.New("malformed token")
+	ErrAlgorithm = errors.New("unsupported signing algorithm")
+	ErrSignature = errors.New("invalid token signature")
+	ErrExpired   = errors.New("token is expired")
+	ErrNotYet    = errors.New("token is not valid yet")
+)
+
+// Leeway Допустимое расхождение часов при проверке времени (токен принимается еще Leeway после истечения)
+const Leeway = 30 * time.Second
+
+// Claims Поля токена
+type Claims struct {
+	ID        string `json:"jti"` // Уникальный ид токена (для отзыва)
+	Subject   string `json:"sub"` // Имя пользователя
+	IssuedAt  int64  `json:"iat"` // Время выдачи (Unix)
+	ExpiresAt int64  `json:"exp"` // Время окончания действия (Unix)
+}
+
+// header Заголовок токена
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+var encoding = base64.RawURLEncoding
+
+// encodedHeader Заголовок HS256 - один и тот же для всех токенов
+var encodedHeader = mustEncode(header{Alg: "HS256", Typ: "JWT"})
+
+// Sign Создает токен с подписью HS256
+func Sign(claims Claims, secret []byte) (string, error) {
+	const op = "lib.jwt.Sign"
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("%s: marshal claims: %w", op, err)
+	}
+
+	unsigned := encodedHeader + "." + encoding.EncodeToString(payload)
+
+	return unsigned + "." + encoding.EncodeToString(sign(unsigned, secret)), nil
+}
+
+// Parse Проверяет подпись и срок действия токена и возвращает его поля
+func Parse(token string, secret []byte, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrMalformed
+	}
+
+	rawHeader, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+	var h header
+	if err := json.Unmarshal(rawHeader, &h); err != nil {
+		return Claims{}, ErrMalformed
+	}
+	// Алгоритм задает сервер, а не токен: иначе можно подсунуть "none" или другой ключ
+	if h.Alg != "HS256" {
+		return Claims{}, ErrAlgorithm
+	}
+
+	signature, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+	if !hmac.Equal(signature, sign(parts[0]+"."+parts[1], secret)) {
+		return Claims{}, ErrSignature
+	}
+
+	payload, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return Claims{}, ErrMalformed
+	}
+
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(Leeway)) {
+		return Claims{}, ErrExpired
+	}
+	if claims.IssuedAt != 0 && now.Add(Leeway).Before(time.Unix(claims.IssuedAt, 0)) {
+		return Claims{}, ErrNotYet
+	}
+
+	return claims, nil
+}
+
+// sign Подпись HMAC-SHA256
+func sign(unsigned string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+
+	return mac.Sum(nil)
+}
+
+func mustEncode(v any) string {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return encoding.EncodeToString(raw)
+}
@@ -0,0 +1,56 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignParse(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Unix(1_700_000_000, 0)
+	valid := Claims{ID: "id", Subject: "user", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix()}
+
+	signed := func(c Claims, secret []byte) string {
+		token, err := Sign(c, secret)
+		if err != nil {
+			t.Fatalf("Sign: %v", err)
+		}
+		return token
+	}
+	token := signed(valid, secret)
+	parts := strings.Split(token, ".")
+
+	tests := []struct {
+		name    string
+		token   string
+		now     time.Time
+		wantErr error
+	}{
+		{name: "valid", token: token, now: now},
+		{name: "within Leeway after expiry", token: token, now: now.Add(time.Hour + Leeway/2)},
+		{name: "expired", token: token, now: now.Add(time.Hour + Leeway + time.Second), wantErr: ErrExpired},
+		{name: "issued in the future", token: token, now: now.Add(-Leeway - time.Second), wantErr: ErrNotYet},
+		{name: "no expiry", token: signed(Claims{ID: "id", Subject: "user"}, secret), now: now, wantErr: ErrExpired},
+		{name: "other secret", token: signed(valid, []byte("other")), now: now, wantErr: ErrSignature},
+		{name: "tampered payload", token: parts[0] + "." + encoding.EncodeToString([]byte(`{"sub":"admin"}`)) + "." + parts[2], now: now, wantErr: ErrSignature},
+		{name: "alg none", token: encoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + parts[1] + ".", now: now, wantErr: ErrAlgorithm},
+		{name: "two parts", token: parts[0] + "." + parts[1], now: now, wantErr: ErrMalformed},
+		{name: "bad header encoding", token: "!." + parts[1] + "." + parts[2], now: now, wantErr: ErrMalformed},
+		{name: "bad signature encoding", token: parts[0] + "." + parts[1] + ".!", now: now, wantErr: ErrMalformed},
+		{name: "empty", token: "", now: now, wantErr: ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := Parse(tt.token, secret, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && claims != valid {
+				t.Errorf("Parse() = %+v, want %+v", claims, valid)
+			}
+		})
+	}
+}
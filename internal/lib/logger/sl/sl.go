@@ -3,7 +3,10 @@
 package sl
 
 import (
+	"context"
 	"log/slog"
+
+	"github.com/go-chi/chi/v5/middleware"
 )
 
 func Err(err error) slog.Attr {
@@ -12,3 +15,14 @@ func Err(err error) slog.Attr {
 		Value: slog.StringValue(err.Error()),
 	}
 }
+
+// RequestID Ид запроса из контекста (middleware.RequestID), чтобы сообщения из глубины (хранилище, кэш) можно было связать с запросом.
+// Вне запроса - пустой атрибут, который обработчики slog пропускают
+func RequestID(ctx context.Context) slog.Attr {
+	id := middleware.GetReqID(ctx)
+	if id == "" {
+		return slog.Attr{}
+	}
+
+	return slog.String("request_id", id)
+}
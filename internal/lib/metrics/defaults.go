@@ -0,0 +1,48 @@
+// internal/lib/metrics/defaults.go
+
+package metrics
+
+import (
+	"runtime"
+	"time"
+)
+
+// Default Реестр метрик сервиса, выводится на /metrics
+var Default = NewRegistry()
+
+var (
+	// HTTPRequestsTotal Количество HTTP-запросов по шаблону маршрута chi, методу и коду ответа
+	HTTPRequestsTotal = Default.NewCounterVec("http_requests_total",
+		"Total number of HTTP requests.", "method", "route", "status")
+
+	// HTTPRequestDuration Время обработки HTTP-запросов
+	HTTPRequestDuration = Default.NewHistogramVec("http_request_duration_seconds",
+		"HTTP request latency in seconds.", DefBuckets, "method", "route", "status")
+
+	// CacheOperationsTotal Операции с кэшем. result: hit, miss, ok или error
+	CacheOperationsTotal = Default.NewCounterVec("cache_operations_total",
+		"Total number of cache operations by result.", "backend", "operation", "result")
+
+	// StorageQueryDuration Время выполнения методов хранилища
+	StorageQueryDuration = Default.NewHistogramVec("storage_query_duration_seconds",
+		"Storage method latency in seconds.", []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}, "method")
+
+	_ = Default.NewGaugeFunc("go_goroutines", "Number of goroutines that currently exist.", func() float64 {
+		return float64(runtime.NumGoroutine())
+	})
+)
+
+// Результаты операций с кэшем
+const (
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheOK    = "ok"
+	CacheError = "error"
+)
+
+// ObserveStorage Учитывает время выполнения метода хранилища. Использование:
+//
+//	defer metrics.ObserveStorage("GetData", time.Now())
+func ObserveStorage(method string, start time.Time) {
+	StorageQueryDuration.Observe(time.Since(start).Seconds(), method)
+}
@@ -0,0 +1,45 @@
+// internal/lib/retry/retry.go
+
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// maxBackoff Верхняя граница паузы между попытками
+const maxBackoff = 30 * time.Second
+
+// Do Вызывает fn, пока она не завершится без ошибки или не закончатся попытки.
+// Пауза между попытками начинается с backoff и удваивается (не более maxBackoff).
+// onRetry (если задана) вызывается после каждой неудачной попытки, кроме последней.
+// Возвращает ошибку последней попытки или ошибку контекста
+func Do(ctx context.Context, attempts int, backoff time.Duration, fn func(ctx context.Context) error, onRetry func(attempt int, err error)) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if attempt == attempts {
+			break
+		}
+
+		if onRetry != nil {
+			onRetry(attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, maxBackoff)
+	}
+
+	return err
+}
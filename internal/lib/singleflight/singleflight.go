@@ -0,0 +1,58 @@
+// internal/lib/singleflight/singleflight.go
+
+// Объединение одновременных запросов с одинаковым ключом (по мотивам golang.org/x/sync/singleflight):
+// пока выполняется первый запрос, остальные ждут его результата вместо того, чтобы идти в БД самостоятельно
+package singleflight
+
+import (
+	"errors"
+	"sync"
+)
+
+// errPanicked Результат для ожидающих, если fn завершилась паникой
+var errPanicked = errors.New("singleflight: function panicked")
+
+// call Выполняющийся (или уже выполненный) запрос
+type call struct {
+	wg  sync.WaitGroup
+	val any
+	err error
+}
+
+// Group Группа запросов. Нулевое значение готово к использованию
+type Group struct {
+	mu sync.Mutex
+	m  map[string]*call
+}
+
+// Do Выполняет fn, если для ключа key нет выполняющегося запроса, иначе ждет его результата.
+// shared == true означает, что результат получен от чужого запроса (fn не вызывалась)
+func (g *Group) Do(key string, fn func() (any, error)) (v any, err error, shared bool) {
+	g.mu.Lock()
+	if g.m == nil {
+		g.m = make(map[string]*call)
+	}
+	if c, ok := g.m[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.val, c.err, true
+	}
+
+	c := new(call)
+	c.wg.Add(1)
+	g.m[key] = c
+	g.mu.Unlock()
+
+	// Ключ удаляем даже при панике в fn, иначе ожидающие зависнут навсегда
+	defer func() {
+		g.mu.Lock()
+		delete(g.m, key)
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+
+	c.err = errPanicked
+	c.val, c.err = fn()
+
+	return c.val, c.err, false
+}
@@ -0,0 +1,98 @@
+package singleflight
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDo(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name    string
+		fn      func() (any, error)
+		want    any
+		wantErr error
+	}{
+		{name: "value", fn: func() (any, error) { return 42, nil }, want: 42},
+		{name: "error", fn: func() (any, error) { return nil, errFailed }, wantErr: errFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var g Group
+
+			// Последовательные вызовы не объединяются: каждый выполняет fn сам
+			for range 2 {
+				v, err, shared := g.Do("key", tt.fn)
+				if v != tt.want || !errors.Is(err, tt.wantErr) || shared {
+					t.Errorf("Do() = %v, %v, %v, want %v, %v, false", v, err, shared, tt.want, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestDo_Concurrent(t *testing.T) {
+	const callers = 10
+
+	var (
+		g       Group
+		calls   atomic.Int32
+		shared  atomic.Int32
+		wg      sync.WaitGroup
+		release = make(chan struct{})
+	)
+	fn := func() (any, error) {
+		calls.Add(1)
+		<-release
+		return "value", nil
+	}
+
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err, sh := g.Do("key", fn)
+			if v != "value" || err != nil {
+				t.Errorf("Do() = %v, %v, want value, nil", v, err)
+			}
+			if sh {
+				shared.Add(1)
+			}
+		}()
+	}
+	// Даем вызовам встать в ожидание первого
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load() + shared.Load(); got != callers {
+		t.Errorf("calls + shared = %d, want %d", got, callers)
+	}
+	if calls.Load() >= callers {
+		t.Errorf("fn called %d times, concurrent calls were not coalesced", calls.Load())
+	}
+}
+
+func TestDo_PanicReleasesKey(t *testing.T) {
+	var g Group
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Do() did not propagate panic")
+			}
+		}()
+		g.Do("key", func() (any, error) { panic("boom") })
+	}()
+
+	// Ключ освобожден: следующий вызов выполняет fn, а не ждет навсегда
+	v, err, shared := g.Do("key", func() (any, error) { return "value", nil })
+	if v != "value" || err != nil || shared {
+		t.Errorf("Do() after panic = %v, %v, %v", v, err, shared)
+	}
+}
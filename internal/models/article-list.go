@@ -0,0 +1,27 @@
+package models
+
+// Поля сортировки списка статей
+const (
+	SortById     = "id"
+	SortByTitle  = "title"
+	SortByRating = "rating"
+)
+
+// ArticleListQuery Параметры выборки списка статей
+type ArticleListQuery struct {
+	Sort        string   // id, title или rating
+	Desc        bool     // Сортировка по убыванию
+	Limit       int      // Размер страницы
+	Offset      int      // Смещение (пагинация по номеру страницы)
+	AfterId     int64    // Курсор: ид последней статьи предыдущей страницы (только при сортировке по id)
+	MinRating   *float64 // Только статьи с рейтингом не ниже заданного
+	TitlePrefix string   // Только статьи, заголовок которых начинается с заданной строки
+}
+
+// ArticlePage Страница списка статей
+type ArticlePage struct {
+	Articles []ArticleInfo `json:"articles"`
+	Total    int           `json:"total"`    // Количество статей, подходящих под фильтры
+	HasMore  bool          `json:"has_more"` // Есть ли следующая страница
+	NextId   int64         `json:"next_id"`  // Курсор для следующей страницы (0, если страниц больше нет или сортировка не по id)
+}
@@ -0,0 +1,11 @@
+package models
+
+// CacheStats Счетчики работы кэша статей. Сэкономленные запросы к БД - попадания в кэш (включая негативные) и объединенные запросы
+type CacheStats struct {
+	CacheHits         int64 `json:"cache_hits"`         // Статья найдена в кэше
+	NegativeHits      int64 `json:"negative_hits"`      // В кэше есть отметка, что статьи с таким ид нет
+	CacheMisses       int64 `json:"cache_misses"`       // В кэше ничего не найдено
+	CoalescedRequests int64 `json:"coalesced_requests"` // Запрос дождался результата такого же параллельного запроса
+	DBQueries         int64 `json:"db_queries"`         // Фактически выполненные запросы к БД
+	DBQueriesSaved    int64 `json:"db_queries_saved"`
+}
@@ -0,0 +1,8 @@
+package models
+
+type Comment struct {
+	Id        int64    `db:"id" json:"id"`
+	ArticleId int64    `db:"article_id" json:"article_id"`
+	Text      string   `db:"text" json:"text"`
+	Score     *float64 `db:"score" json:"score"` // Используем указатель, поскольку в БД может быть значение null
+}
@@ -0,0 +1,14 @@
+package models
+
+// Окна рейтинга лучших статей
+const (
+	WindowDay  = "day"  // Оценки за текущие сутки (UTC)
+	WindowWeek = "week" // Оценки за последние 7 суток
+	WindowAll  = "all"  // Средний рейтинг за все время
+)
+
+// RankedArticle Статья в рейтинге
+type RankedArticle struct {
+	ArticleInfo
+	Score float64 `json:"score"` // Оценка статьи в рейтинге (средняя оценка или активность)
+}
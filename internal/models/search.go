@@ -0,0 +1,16 @@
+package models
+
+// SearchHit Статья, найденная полнотекстовым поиском
+type SearchHit struct {
+	Id      int64   `db:"id" json:"id"`
+	Title   string  `db:"title" json:"title"`     // Заголовок с выделенными совпадениями
+	Snippet string  `db:"snippet" json:"snippet"` // Фрагмент текста с выделенными совпадениями
+	Rank    float64 `db:"rank" json:"rank"`       // Релевантность bm25 (чем меньше, тем выше в выдаче)
+}
+
+// SearchPage Страница результатов поиска
+type SearchPage struct {
+	Hits    []SearchHit `json:"hits"`
+	Total   int         `json:"total"` // Количество найденных статей
+	HasMore bool        `json:"has_more"`
+}
@@ -0,0 +1,325 @@
+// internal/storage/migrator/migrator.go
+
+// Версионированные миграции схемы БД. Примененные версии хранятся в таблице schema_migrations,
+// каждая миграция выполняется в отдельной транзакции вместе с записью о ее применении
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrUnknownVersion = errors.New("unknown migration version")
+
+// fileNameRe Имя файла миграции: 0001_init.up.sql
+var fileNameRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+const schemaMigrations = `
+CREATE TABLE IF NOT EXISTS schema_migrations(
+	version INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP);
+`
+
+// Migration Миграция схемы
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status Состояние миграции
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Migrator Применяет и откатывает миграции
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration // по возрастанию версии
+}
+
+// New Конструктор объекта Migrator. Миграции читаются из корня fsys
+func New(db *sqlx.DB, fsys fs.FS) (*Migrator, error) {
+	const op = "storage.migrator.New"
+
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// load Читает и проверяет файлы миграций
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		m := fileNameRe.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(m[1])
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		}
+		if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has different names: %s and %s", version, mig.Name, m[2])
+		}
+
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Latest Последняя известная версия схемы (0 - миграций нет)
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version Текущая версия схемы БД (0 - миграции не применялись)
+func (m *Migrator) Version() (int, error) {
+	const op = "storage.migrator.Version"
+
+	exists, err := m.tableExists()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	var version int
+	if err := m.db.Get(&version, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations"); err != nil {
+		return 0, fmt.Errorf("%s: select: %w", op, err)
+	}
+
+	return version, nil
+}
+
+// Status Состояние всех известных миграций
+func (m *Migrator) Status() ([]Status, error) {
+	const op = "storage.migrator.Status"
+
+	exists, err := m.tableExists()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var applied []struct {
+		Version   int       `db:"version"`
+		AppliedAt time.Time `db:"applied_at"`
+	}
+	if exists {
+		if err := m.db.Select(&applied, "SELECT version, applied_at FROM schema_migrations"); err != nil {
+			return nil, fmt.Errorf("%s: select: %w", op, err)
+		}
+	}
+
+	appliedAt := make(map[int]time.Time, len(applied))
+	for _, a := range applied {
+		appliedAt[a.Version] = a.AppliedAt
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := Status{Version: mig.Version, Name: mig.Name}
+		if t, ok := appliedAt[mig.Version]; ok {
+			st.Applied = true
+			st.AppliedAt = &t
+		}
+		statuses = append(statuses, st)
+	}
+
+	return statuses, nil
+}
+
+// Up Применяет все непримененные миграции. Возвращает примененные
+func (m *Migrator) Up() ([]Migration, error) {
+	return m.To(m.Latest())
+}
+
+// Down Откатывает последнюю примененную миграцию. Возвращает откаченные
+func (m *Migrator) Down() ([]Migration, error) {
+	const op = "storage.migrator.Down"
+
+	current, err := m.Version()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if current == 0 {
+		return nil, nil
+	}
+
+	// Целевая версия - предыдущая перед текущей
+	target := 0
+	for _, mig := range m.migrations {
+		if mig.Version < current {
+			target = mig.Version
+		}
+	}
+
+	return m.To(target)
+}
+
+// To Применяет или откатывает миграции до версии version (0 - откатить все).
+// Возвращает выполненные миграции в порядке выполнения
+func (m *Migrator) To(version int) ([]Migration, error) {
+	const op = "storage.migrator.To"
+
+	if version != 0 && !m.known(version) {
+		return nil, fmt.Errorf("%s: %w: %d", op, ErrUnknownVersion, version)
+	}
+
+	if err := m.ensureTable(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	current, err := m.Version()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var done []Migration
+	switch {
+	case version > current:
+		for _, mig := range m.migrations {
+			if mig.Version <= current || mig.Version > version {
+				continue
+			}
+			if err := m.apply(mig); err != nil {
+				return done, fmt.Errorf("%s: %w", op, err)
+			}
+			done = append(done, mig)
+		}
+	case version < current:
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if mig.Version > current || mig.Version <= version {
+				continue
+			}
+			if err := m.revert(mig); err != nil {
+				return done, fmt.Errorf("%s: %w", op, err)
+			}
+			done = append(done, mig)
+		}
+	}
+
+	return done, nil
+}
+
+// Close Закрывает соединение с БД
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// apply Применяет миграцию и записывает ее версию (в одной транзакции)
+func (m *Migrator) apply(mig Migration) error {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(mig.Up); err != nil {
+		return fmt.Errorf("apply migration %d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", mig.Version, mig.Name); err != nil {
+		return fmt.Errorf("record migration %d_%s: %w", mig.Version, mig.Name, err)
+	}
+
+	return tx.Commit()
+}
+
+// revert Откатывает миграцию и удаляет запись о ней (в одной транзакции)
+func (m *Migrator) revert(mig Migration) error {
+	if mig.Down == "" {
+		return fmt.Errorf("migration %d_%s has no down script", mig.Version, mig.Name)
+	}
+
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(mig.Down); err != nil {
+		return fmt.Errorf("revert migration %d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if _, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", mig.Version); err != nil {
+		return fmt.Errorf("delete migration record %d_%s: %w", mig.Version, mig.Name, err)
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) ensureTable() error {
+	if _, err := m.db.Exec(schemaMigrations); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// tableExists Проверяет, что таблица schema_migrations уже создана (чтение версии не должно менять БД)
+func (m *Migrator) tableExists() (bool, error) {
+	var exists bool
+	if err := m.db.Get(&exists, "SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations')"); err != nil {
+		return false, fmt.Errorf("check schema_migrations: %w", err)
+	}
+	return exists, nil
+}
+
+func (m *Migrator) known(version int) bool {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,244 @@
+package migrator
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// testMigrations Три миграции; файлы, не являющиеся миграциями, и каталоги пропускаются
+var testMigrations = fstest.MapFS{
+	"0001_users.up.sql":         {Data: []byte("CREATE TABLE users(id INTEGER PRIMARY KEY);")},
+	"0001_users.down.sql":       {Data: []byte("DROP TABLE users;")},
+	"0002_users_name.up.sql":    {Data: []byte("ALTER TABLE users ADD COLUMN name TEXT;")},
+	"0002_users_name.down.sql":  {Data: []byte("ALTER TABLE users DROP COLUMN name;")},
+	"0003_posts.up.sql":         {Data: []byte("CREATE TABLE posts(id INTEGER PRIMARY KEY);")},
+	"0003_posts.down.sql":       {Data: []byte("DROP TABLE posts;")},
+	"README.md":                 {Data: []byte("not a migration")},
+	"0004_skipped_dir.up.sql/x": {Data: []byte("directories are ignored")},
+}
+
+func newTestMigrator(t *testing.T, fsys fstest.MapFS) *Migrator {
+	t.Helper()
+
+	db, err := sqlx.Connect("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := New(db, fsys)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return m
+}
+
+func versions(migrations []Migration) []int {
+	res := make([]int, 0, len(migrations))
+	for _, mig := range migrations {
+		res = append(res, mig.Version)
+	}
+	return res
+}
+
+func tableExists(t *testing.T, m *Migrator, name string) bool {
+	t.Helper()
+
+	var exists bool
+	if err := m.db.Get(&exists, "SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = ?)", name); err != nil {
+		t.Fatal(err)
+	}
+	return exists
+}
+
+func TestMigrator(t *testing.T) {
+	type step struct {
+		action      string // up, down, to
+		to          int
+		wantDone    []int
+		wantVersion int
+		wantErr     error
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "up applies all",
+			steps: []step{
+				{action: "up", wantDone: []int{1, 2, 3}, wantVersion: 3},
+				{action: "up", wantDone: []int{}, wantVersion: 3},
+			},
+		},
+		{
+			name: "down reverts one at a time",
+			steps: []step{
+				{action: "up", wantDone: []int{1, 2, 3}, wantVersion: 3},
+				{action: "down", wantDone: []int{3}, wantVersion: 2},
+				{action: "down", wantDone: []int{2}, wantVersion: 1},
+				{action: "down", wantDone: []int{1}, wantVersion: 0},
+				{action: "down", wantDone: []int{}, wantVersion: 0},
+			},
+		},
+		{
+			name: "to moves in both directions",
+			steps: []step{
+				{action: "to", to: 2, wantDone: []int{1, 2}, wantVersion: 2},
+				{action: "to", to: 3, wantDone: []int{3}, wantVersion: 3},
+				{action: "to", to: 1, wantDone: []int{3, 2}, wantVersion: 1},
+				{action: "to", to: 0, wantDone: []int{1}, wantVersion: 0},
+			},
+		},
+		{
+			name: "to unknown version",
+			steps: []step{
+				{action: "to", to: 7, wantErr: ErrUnknownVersion, wantVersion: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMigrator(t, testMigrations)
+
+			for i, st := range tt.steps {
+				var (
+					done []Migration
+					err  error
+				)
+				switch st.action {
+				case "up":
+					done, err = m.Up()
+				case "down":
+					done, err = m.Down()
+				case "to":
+					done, err = m.To(st.to)
+				}
+				if !errors.Is(err, st.wantErr) {
+					t.Fatalf("step %d %s: error = %v, want %v", i, st.action, err, st.wantErr)
+				}
+				if st.wantErr == nil && !slices.Equal(versions(done), st.wantDone) {
+					t.Errorf("step %d %s: done = %v, want %v", i, st.action, versions(done), st.wantDone)
+				}
+
+				version, err := m.Version()
+				if err != nil {
+					t.Fatalf("step %d: Version() error = %v", i, err)
+				}
+				if version != st.wantVersion {
+					t.Errorf("step %d %s: version = %d, want %d", i, st.action, version, st.wantVersion)
+				}
+				if got, want := tableExists(t, m, "posts"), st.wantVersion >= 3; got != want {
+					t.Errorf("step %d %s: table posts exists = %v, want %v", i, st.action, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrator_Status(t *testing.T) {
+	m := newTestMigrator(t, testMigrations)
+
+	// Статус и версия читаются без создания schema_migrations
+	statuses, err := m.Status()
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if tableExists(t, m, "schema_migrations") {
+		t.Error("Status() created schema_migrations")
+	}
+	for _, st := range statuses {
+		if st.Applied || st.AppliedAt != nil {
+			t.Errorf("migration %d applied before Up()", st.Version)
+		}
+	}
+
+	if _, err := m.To(2); err != nil {
+		t.Fatalf("To(2) error = %v", err)
+	}
+
+	statuses, err = m.Status()
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	want := []Status{{Version: 1, Name: "users", Applied: true}, {Version: 2, Name: "users_name", Applied: true}, {Version: 3, Name: "posts"}}
+	if len(statuses) != len(want) {
+		t.Fatalf("Status() = %+v, want %d migrations", statuses, len(want))
+	}
+	for i, st := range statuses {
+		if st.Version != want[i].Version || st.Name != want[i].Name || st.Applied != want[i].Applied {
+			t.Errorf("Status()[%d] = %+v, want %+v", i, st, want[i])
+		}
+		if st.Applied != (st.AppliedAt != nil) {
+			t.Errorf("Status()[%d]: applied = %v, applied_at = %v", i, st.Applied, st.AppliedAt)
+		}
+	}
+}
+
+func TestMigrator_Failures(t *testing.T) {
+	t.Run("failed migration is not recorded", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"0001_users.up.sql":  {Data: []byte("CREATE TABLE users(id INTEGER PRIMARY KEY);")},
+			"0002_broken.up.sql": {Data: []byte("CREATE TABLE users(id INTEGER PRIMARY KEY);")},
+		}
+		m := newTestMigrator(t, fsys)
+
+		done, err := m.Up()
+		if err == nil {
+			t.Fatal("Up() error = nil, want error")
+		}
+		if !slices.Equal(versions(done), []int{1}) {
+			t.Errorf("Up() done = %v, want [1]", versions(done))
+		}
+		if version, _ := m.Version(); version != 1 {
+			t.Errorf("version = %d, want 1", version)
+		}
+	})
+
+	t.Run("no down script", func(t *testing.T) {
+		m := newTestMigrator(t, fstest.MapFS{"0001_users.up.sql": {Data: []byte("CREATE TABLE users(id INTEGER PRIMARY KEY);")}})
+
+		if _, err := m.Up(); err != nil {
+			t.Fatalf("Up() error = %v", err)
+		}
+		if _, err := m.Down(); err == nil {
+			t.Fatal("Down() error = nil, want error")
+		}
+		if version, _ := m.Version(); version != 1 {
+			t.Errorf("version = %d, want 1", version)
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		wantErr bool
+	}{
+		{name: "valid", fsys: testMigrations},
+		{name: "empty", fsys: fstest.MapFS{}},
+		{name: "invalid name", fsys: fstest.MapFS{"init.sql": {Data: []byte("SELECT 1;")}}, wantErr: true},
+		{name: "no up script", fsys: fstest.MapFS{"0001_users.down.sql": {Data: []byte("DROP TABLE users;")}}, wantErr: true},
+		{name: "different names", fsys: fstest.MapFS{
+			"0001_users.up.sql":    {Data: []byte("CREATE TABLE users(id INTEGER);")},
+			"0001_people.down.sql": {Data: []byte("DROP TABLE users;")},
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := load(tt.fsys); (err != nil) != tt.wantErr {
+				t.Errorf("load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+// internal/storage/sqlite/cache.go
+
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"test-redis/internal/models"
+	"test-redis/internal/storage"
+	"time"
+)
+
+// notFoundMarker Значение в кэше, означающее, что статьи с таким ид нет (негативное кэширование)
+var notFoundMarker = []byte("!not-found")
+
+// cacheStats Счетчики работы кэша статей
+type cacheStats struct {
+	cacheHits    atomic.Int64
+	negativeHits atomic.Int64
+	cacheMisses  atomic.Int64
+	coalesced    atomic.Int64
+	dbQueries    atomic.Int64
+}
+
+func (cs *cacheStats) snapshot() models.CacheStats {
+	stats := models.CacheStats{
+		CacheHits:         cs.cacheHits.Load(),
+		NegativeHits:      cs.negativeHits.Load(),
+		CacheMisses:       cs.cacheMisses.Load(),
+		CoalescedRequests: cs.coalesced.Load(),
+		DBQueries:         cs.dbQueries.Load(),
+	}
+	stats.DBQueriesSaved = stats.CacheHits + stats.NegativeHits + stats.CoalescedRequests
+
+	return stats
+}
+
+// FlushableNamespaces Пространства имен кэша, которые можно сбрасывать вручную (/admin/cache/keys): статьи, списки и поиск -
+// копии данных БД, которые перечитаются при следующем запросе. Остальные ключи (views:, {lb}:, ratelimit:, jwt:)
+// хранят данные, которых нет в БД, и сбрасываться не должны
+var FlushableNamespaces = []string{"article:", "list:", "search:"}
+
+// articleKey Ключ статьи в кэше
+func articleKey(id int64) string {
+	return "article:" + strconv.FormatInt(id, 10)
+}
+
+// getCachedArticle Получение данных о статье из кэша. Если статьи в кэше нет - cache.ErrDataNotFound,
+// если в кэше отметка об отсутствии статьи - storage.ErrDataNotFound
+func (s *Storage) getCachedArticle(ctx context.Context, id int64) (models.ArticleInfo, error) {
+	const op = "storage.sqlite.getCachedArticle"
+
+	raw, err := s.cache.Get(ctx, articleKey(id))
+	if err != nil {
+		return models.ArticleInfo{}, err
+	}
+	if bytes.Equal(raw, notFoundMarker) {
+		return models.ArticleInfo{}, storage.ErrDataNotFound
+	}
+
+	var info models.ArticleInfo
+	if err := s.codec.Unmarshal(raw, &info); err != nil {
+		return models.ArticleInfo{}, fmt.Errorf("%s: can't unmarshal raw value %s: %w", op, articleKey(id), err)
+	}
+
+	return info, nil
+}
+
+// setCachedArticle Запись данных о статье в кэш
+func (s *Storage) setCachedArticle(ctx context.Context, info models.ArticleInfo) error {
+	const op = "storage.sqlite.setCachedArticle"
+
+	// преобразуем структуру в []byte для хранения в кэше
+	raw, err := s.codec.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("%s: marshal: %w", op, err)
+	}
+
+	// Время жизни определяет кэш (настройки для сущности "article" или по умолчанию)
+	return s.cache.Set(ctx, articleKey(info.Id), raw, 0)
+}
+
+// setCachedNotFound Запись в кэш отметки об отсутствии статьи. negativeTTL <= 0 - негативное кэширование отключено
+// (0 в cache.Set означал бы время жизни по умолчанию, и отметка жила бы как обычная статья)
+func (s *Storage) setCachedNotFound(ctx context.Context, id int64) error {
+	ttl := time.Duration(s.negativeTTL.Load())
+	if ttl <= 0 {
+		return nil
+	}
+
+	return s.cache.Set(ctx, articleKey(id), notFoundMarker, ttl)
+}
+
+// articleGenStripes Количество независимых блокировок поколений статей (статьи распределяются по ид)
+const articleGenStripes = 64
+
+// articleGenerations Поколения статей: номер увеличивается при каждом зафиксированном изменении статьи.
+// Загрузка из БД запоминает поколение до чтения и кладет результат в кэш, только если поколение не изменилось:
+// иначе значение, прочитанное до коммита, могло бы попасть в кэш уже после сброса и жить до истечения TTL.
+// Проверка и запись в кэш выполняются под блокировкой поколения, поэтому запись не может проскочить между
+// увеличением поколения и сбросом кэша.
+// Поколения хранятся в памяти процесса и защищают от гонки загрузок и изменений внутри одного экземпляра сервиса
+type articleGenerations struct {
+	stripes [articleGenStripes]articleGenStripe
+}
+
+type articleGenStripe struct {
+	mu      sync.Mutex
+	gen     map[int64]uint64
+	pending map[int64]struct{} // статьи, сбросить кэш которых после изменения не удалось: кэш для них не читается до повторного сброса
+}
+
+func (g *articleGenerations) stripe(id int64) *articleGenStripe {
+	return &g.stripes[uint64(id)%articleGenStripes]
+}
+
+// update Изменение поколения или отметки статьи под блокировкой
+func (g *articleGenerations) update(id int64, fn func(st *articleGenStripe)) {
+	st := g.stripe(id)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.gen == nil {
+		st.gen = make(map[int64]uint64)
+		st.pending = make(map[int64]struct{})
+	}
+	fn(st)
+}
+
+// articleGen Текущее поколение статьи (запоминается перед чтением статьи из БД)
+func (s *Storage) articleGen(id int64) uint64 {
+	st := s.gens.stripe(id)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.gen[id]
+}
+
+// articleCacheStale Сброс кэша статьи после изменения не удался - значение в кэше может быть устаревшим
+func (s *Storage) articleCacheStale(id int64) bool {
+	st := s.gens.stripe(id)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	_, ok := st.pending[id]
+	return ok
+}
+
+// invalidateCommitted Сброс кэша статей после коммита их изменения: поколения увеличиваются, поэтому загрузки,
+// начатые до коммита, свой результат в кэш уже не положат. Если сбросить не удалось, статьи помечаются,
+// и до успешного повторного сброса (при следующей загрузке) их кэш не читается
+func (s *Storage) invalidateCommitted(ctx context.Context, ids ...int64) error {
+	for _, id := range ids {
+		s.gens.update(id, func(st *articleGenStripe) { st.gen[id]++ })
+	}
+
+	keys := make([]string, 0, len(ids)+2)
+	for _, id := range ids {
+		keys = append(keys, articleKey(id))
+	}
+	err := s.cache.Delete(ctx, append(keys, listVersionKey, searchVersionKey)...)
+
+	for _, id := range ids {
+		s.gens.update(id, func(st *articleGenStripe) {
+			if err != nil {
+				st.pending[id] = struct{}{}
+			} else {
+				delete(st.pending, id)
+			}
+		})
+	}
+
+	return err
+}
+
+// cacheLoaded Кладет в кэш статью (found == false - отметку об отсутствии), прочитанную из БД в поколении gen.
+// Если статья за время чтения изменилась, результат не кэшируется. Незавершенный сброс кэша статьи сначала повторяется
+func (s *Storage) cacheLoaded(ctx context.Context, id int64, gen uint64, article models.ArticleInfo, found bool) error {
+	st := s.gens.stripe(id)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.gen[id] != gen {
+		return nil
+	}
+	if _, ok := st.pending[id]; ok {
+		if err := s.invalidateArticle(ctx, id); err != nil {
+			return err
+		}
+		delete(st.pending, id)
+	}
+
+	if !found {
+		return s.setCachedNotFound(ctx, id)
+	}
+
+	return s.setCachedArticle(ctx, article)
+}
+
+// invalidateArticle Удаляет статью из кэша вместе с версиями выборок, в которые она может входить (списки, результаты поиска)
+func (s *Storage) invalidateArticle(ctx context.Context, articleId int64) error {
+	return s.cache.Delete(ctx, articleKey(articleId), listVersionKey, searchVersionKey)
+}
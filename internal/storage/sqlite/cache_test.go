@@ -0,0 +1,119 @@
+//go:build sqlite_fts5
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"test-redis/internal/cache"
+	"test-redis/internal/storage"
+)
+
+func TestNegativeCaching(t *testing.T) {
+	tests := []struct {
+		name         string
+		negativeTTL  time.Duration
+		wantFoundNow bool // статья, созданная после запроса отсутствующего ид, видна сразу
+	}{
+		{name: "disabled", negativeTTL: 0, wantFoundNow: true},
+		{name: "enabled", negativeTTL: time.Minute, wantFoundNow: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, c := newTestStorage(t)
+			s.SetNegativeTTL(tt.negativeTTL)
+
+			if _, err := s.GetData(ctx, 1); !errors.Is(err, storage.ErrDataNotFound) {
+				t.Fatalf("GetData() of missing id error = %v, want ErrDataNotFound", err)
+			}
+			_, err := c.Get(ctx, articleKey(1))
+			if cached := err == nil; cached == tt.wantFoundNow {
+				t.Errorf("not-found marker cached = %v, want %v", cached, !tt.wantFoundNow)
+			}
+
+			// Статья появляется в обход хранилища (без сброса кэша)
+			insertArticles(t, s, 1)
+
+			_, err = s.GetData(ctx, 1)
+			if found := err == nil; found != tt.wantFoundNow {
+				t.Errorf("GetData() after insert error = %v, want found %v", err, tt.wantFoundNow)
+			}
+		})
+	}
+}
+
+// failingDeleteCache Кэш, сброс ключей в котором не удается, пока задана ошибка
+type failingDeleteCache struct {
+	cache.Cache
+	err error
+}
+
+func (c *failingDeleteCache) Delete(ctx context.Context, keys ...string) error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.Cache.Delete(ctx, keys...)
+}
+
+// TestLoadRacingUpdate Загрузка статьи, прочитавшая ее до коммита изменения, кладет результат в кэш уже после сброса
+func TestLoadRacingUpdate(t *testing.T) {
+	tests := []struct {
+		name      string
+		update    bool
+		deleteErr error
+		wantTitle string
+	}{
+		{name: "no update", wantTitle: "title 1"},
+		{name: "update between read and cache", update: true, wantTitle: "updated"},
+		{name: "update with failed invalidation", update: true, deleteErr: errors.New("cache is down"), wantTitle: "updated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, c := newTestStorage(t)
+			fc := &failingDeleteCache{Cache: c}
+			s.cache = fc
+			insertArticles(t, s, 1)
+
+			// Начало loadArticle: поколение и строка из БД до изменения
+			gen := s.articleGen(1)
+			article, err := s.selectArticle(ctx, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.update {
+				fc.err = tt.deleteErr
+				title := "updated"
+				if err := s.UpdateArticle(ctx, 1, &title, nil); err != nil {
+					t.Fatalf("UpdateArticle() error = %v", err)
+				}
+				fc.err = nil
+			}
+
+			// Конец loadArticle: прочитанное до коммита значение не должно попасть в кэш
+			if err := s.cacheLoaded(ctx, 1, gen, article, true); err != nil {
+				t.Fatalf("cacheLoaded() error = %v", err)
+			}
+
+			for i := range 2 { // второе чтение - из кэша
+				got, err := s.GetData(ctx, 1)
+				if err != nil {
+					t.Fatalf("GetData() error = %v", err)
+				}
+				if got.Title != tt.wantTitle {
+					t.Errorf("read %d: title = %q, want %q", i, got.Title, tt.wantTitle)
+				}
+			}
+			if s.articleCacheStale(1) {
+				t.Error("article cache is still bypassed after a successful load")
+			}
+		})
+	}
+}
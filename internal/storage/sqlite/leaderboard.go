@@ -0,0 +1,285 @@
+// internal/storage/sqlite/leaderboard.go
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"test-redis/internal/cache"
+	"test-redis/internal/models"
+	"test-redis/internal/storage"
+	"time"
+)
+
+// Рейтинги статей хранятся в упорядоченных множествах кэша (Redis ZSET).
+// Все ключи имеют общий hash tag {lb}, чтобы ZUNION работал и в режиме cluster
+const (
+	ratingBoardKey = "{lb}:rating" // средний рейтинг статьи за все время
+
+	boardDays      = 7                                // Сколько суточных множеств учитывается (окно week, trending)
+	boardBucketTTL = (boardDays + 1) * 24 * time.Hour // Время жизни суточного множества
+	viewWeight     = 1.0                              // Вклад просмотра в активность
+	commentWeight  = 5.0                              // Вклад комментария в активность
+	trendingDecay  = 0.5                              // Вес активности уменьшается вдвое за каждые сутки
+)
+
+// activityKey Суточное множество активности (просмотры и комментарии)
+func activityKey(day time.Time) string {
+	return "{lb}:activity:" + day.UTC().Format("20060102")
+}
+
+// ratingSumKey Суточное множество суммы оценок в комментариях
+func ratingSumKey(day time.Time) string {
+	return "{lb}:rating_sum:" + day.UTC().Format("20060102")
+}
+
+// ratingCountKey Суточное множество количества оценок в комментариях
+func ratingCountKey(day time.Time) string {
+	return "{lb}:rating_count:" + day.UTC().Format("20060102")
+}
+
+// boardDaysBack Суточные ключи за последние n суток, начиная с текущих
+func boardDaysBack(n int, key func(time.Time) string) []string {
+	now := time.Now()
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = key(now.AddDate(0, 0, -i))
+	}
+
+	return keys
+}
+
+// recordViewActivity Учесть просмотр статьи в рейтинге активности
+func (s *Storage) recordViewActivity(ctx context.Context, articleId int64) error {
+	member := strconv.FormatInt(articleId, 10)
+
+	return s.boards.ZIncrBy(ctx, activityKey(time.Now()), member, viewWeight, boardBucketTTL)
+}
+
+// recordComment Учесть новый комментарий в рейтингах. Вызывается после фиксации транзакции:
+// рейтинги вторичны по отношению к БД, поэтому ошибки кэша не возвращаются, а только выводятся
+func (s *Storage) recordComment(ctx context.Context, articleId int64, score *float64) {
+	const op = "storage.sqlite.recordComment"
+
+	ctx, cancel := s.detached(ctx)
+	defer cancel()
+
+	member := strconv.FormatInt(articleId, 10)
+	now := time.Now()
+
+	if err := s.boards.ZIncrBy(ctx, activityKey(now), member, commentWeight, boardBucketTTL); err != nil {
+		s.logWarn(ctx, op, "failed to record comment activity", err)
+	}
+	if score != nil {
+		if err := s.boards.ZIncrBy(ctx, ratingSumKey(now), member, *score, boardBucketTTL); err != nil {
+			s.logWarn(ctx, op, "failed to record rating sum", err)
+		}
+		if err := s.boards.ZIncrBy(ctx, ratingCountKey(now), member, 1, boardBucketTTL); err != nil {
+			s.logWarn(ctx, op, "failed to record rating count", err)
+		}
+	}
+
+	s.syncRating(ctx, articleId)
+}
+
+// syncRating Обновить средний рейтинг статьи в множестве рейтинга (статья без оценок из него удаляется).
+// Вызывается после фиксации транзакции, поэтому отмена запроса не прерывает обновление
+func (s *Storage) syncRating(ctx context.Context, articleId int64) {
+	const op = "storage.sqlite.syncRating"
+
+	ctx, cancel := s.detached(ctx)
+	defer cancel()
+
+	var rating *float64
+	if err := s.db.GetContext(ctx, &rating, "SELECT AVG(score) FROM comments WHERE score IS NOT NULL AND article_id = ?", articleId); err != nil {
+		s.logWarn(ctx, op, "failed to select rating", err)
+		return
+	}
+
+	member := strconv.FormatInt(articleId, 10)
+	var err error
+	if rating == nil {
+		err = s.boards.ZRem(ctx, ratingBoardKey, member)
+	} else {
+		err = s.boards.ZAdd(ctx, ratingBoardKey, cache.Member{Name: member, Score: *rating})
+	}
+	if err != nil {
+		s.logWarn(ctx, op, "failed to update rating board", err)
+	}
+}
+
+// removeFromBoards Удалить статью из всех рейтингов (после фиксации удаления, отмена запроса его не прерывает)
+func (s *Storage) removeFromBoards(ctx context.Context, articleId int64) {
+	const op = "storage.sqlite.removeFromBoards"
+
+	ctx, cancel := s.detached(ctx)
+	defer cancel()
+
+	member := strconv.FormatInt(articleId, 10)
+
+	keys := []string{ratingBoardKey}
+	keys = append(keys, boardDaysBack(boardDays, activityKey)...)
+	keys = append(keys, boardDaysBack(boardDays, ratingSumKey)...)
+	keys = append(keys, boardDaysBack(boardDays, ratingCountKey)...)
+
+	for _, key := range keys {
+		if err := s.boards.ZRem(ctx, key, member); err != nil {
+			s.logWarn(ctx, op, "failed to remove article from boards", err)
+			return
+		}
+	}
+}
+
+// SyncRatings Заполнить множество рейтинга средними оценками из БД (при запуске, после генерации тестовых данных)
+func (s *Storage) SyncRatings(ctx context.Context) error {
+	const op = "storage.sqlite.SyncRatings"
+	const chunk = 1000
+
+	var rows []struct {
+		Id     int64   `db:"article_id"`
+		Rating float64 `db:"rating"`
+	}
+	if err := s.db.SelectContext(ctx, &rows, `SELECT article_id, AVG(score) AS rating FROM comments
+		WHERE score IS NOT NULL GROUP BY article_id`); err != nil {
+		return fmt.Errorf("%s: select ratings: %w", op, err)
+	}
+
+	members := make([]cache.Member, 0, chunk)
+	for i, row := range rows {
+		members = append(members, cache.Member{Name: strconv.FormatInt(row.Id, 10), Score: row.Rating})
+		if len(members) == chunk || i == len(rows)-1 {
+			if err := s.boards.ZAdd(ctx, ratingBoardKey, members...); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			members = members[:0]
+		}
+	}
+
+	return nil
+}
+
+// GetTrending Самые обсуждаемые и просматриваемые статьи за последние сутки.
+// Активность за каждые предыдущие сутки учитывается с весом, убывающим вдвое
+func (s *Storage) GetTrending(ctx context.Context, limit int) ([]models.RankedArticle, error) {
+	const op = "storage.sqlite.GetTrending"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	keys := boardDaysBack(boardDays, activityKey)
+	weights := make([]float64, len(keys))
+	for i := range weights {
+		weights[i] = math.Pow(trendingDecay, float64(i))
+	}
+
+	// Берем с запасом: статьи могли быть удалены
+	members, err := s.boards.ZUnion(ctx, keys, weights, 2*limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	articles, err := s.rankedArticles(ctx, members, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return articles, nil
+}
+
+// GetTop Статьи с наибольшим средним рейтингом: за все время (models.WindowAll)
+// или по оценкам, поставленным за текущие сутки (models.WindowDay) либо неделю (models.WindowWeek)
+func (s *Storage) GetTop(ctx context.Context, window string, limit int) ([]models.RankedArticle, error) {
+	const op = "storage.sqlite.GetTop"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var (
+		members []cache.Member
+		err     error
+	)
+	switch window {
+	case models.WindowAll:
+		members, err = s.boards.ZTop(ctx, ratingBoardKey, 2*limit)
+	case models.WindowDay:
+		members, err = s.windowRatings(ctx, 1)
+	case models.WindowWeek:
+		members, err = s.windowRatings(ctx, boardDays)
+	default:
+		return nil, fmt.Errorf("%s: unknown window %q", op, window)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	articles, err := s.rankedArticles(ctx, members, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return articles, nil
+}
+
+// windowRatings Средние оценки статей за последние days суток по убыванию.
+// Суточные множества хранят сумму и количество оценок, поэтому среднее считается здесь
+func (s *Storage) windowRatings(ctx context.Context, days int) ([]cache.Member, error) {
+	sums, err := s.boards.ZUnion(ctx, boardDaysBack(days, ratingSumKey), nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.boards.ZUnion(ctx, boardDaysBack(days, ratingCountKey), nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	countByName := make(map[string]float64, len(counts))
+	for _, m := range counts {
+		countByName[m.Name] = m.Score
+	}
+
+	res := make([]cache.Member, 0, len(sums))
+	for _, m := range sums {
+		if count := countByName[m.Name]; count > 0 {
+			res = append(res, cache.Member{Name: m.Name, Score: m.Score / count})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].Name < res[j].Name
+	})
+
+	return res, nil
+}
+
+// rankedArticles Загружает статьи рейтинга (через кэш статей), пропуская удаленные
+func (s *Storage) rankedArticles(ctx context.Context, members []cache.Member, limit int) ([]models.RankedArticle, error) {
+	res := make([]models.RankedArticle, 0, limit)
+	for _, m := range members {
+		if len(res) == limit {
+			break
+		}
+
+		id, err := strconv.ParseInt(m.Name, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		article, err := s.getArticle(ctx, id)
+		if errors.Is(err, storage.ErrDataNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, models.RankedArticle{ArticleInfo: article, Score: m.Score})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,191 @@
+// internal/storage/sqlite/list.go
+
+package sqlite
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"test-redis/internal/cache"
+	"test-redis/internal/lib/metrics"
+	"test-redis/internal/models"
+	"time"
+)
+
+// listVersionKey Ключ версии списков статей в кэше (см. cacheVersion)
+const listVersionKey = "list:version"
+
+// ListArticles Получить страницу списка статей с сортировкой и фильтрами.
+// Результат кэшируется по сигнатуре запроса
+func (s *Storage) ListArticles(ctx context.Context, q models.ArticleListQuery) (models.ArticlePage, error) {
+	const op = "storage.sqlite.ListArticles"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	version, err := s.cacheVersion(ctx, listVersionKey)
+	if err != nil {
+		// Без версии кэшировать нельзя - можно отдать устаревшую страницу
+		s.logWarn(ctx, op, "failed to get list cache version, caching skipped", err)
+		page, err := s.selectArticlePage(ctx, q)
+		if err != nil {
+			return models.ArticlePage{}, fmt.Errorf("%s: %w", op, err)
+		}
+		return page, nil
+	}
+
+	key := listKey(version, q)
+	raw, err := s.cache.Get(ctx, key)
+	switch {
+	case err == nil:
+		var page models.ArticlePage
+		if err := s.codec.Unmarshal(raw, &page); err != nil {
+			s.logWarn(ctx, op, "can't unmarshal cached page "+key, err)
+			break
+		}
+		return page, nil
+	case !errors.Is(err, cache.ErrDataNotFound):
+		s.logWarn(ctx, op, "failed to get page from cache", err)
+	}
+
+	page, err := s.selectArticlePage(ctx, q)
+	if err != nil {
+		return models.ArticlePage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if raw, err := s.codec.Marshal(page); err != nil {
+		s.logWarn(ctx, op, "failed to marshal page", err)
+	} else if err := s.cache.Set(ctx, key, raw, 0); err != nil {
+		s.logWarn(ctx, op, "failed to cache page", err)
+	}
+
+	return page, nil
+}
+
+// cacheVersion Текущая версия группы закэшированных выборок (списков, результатов поиска); если ее нет в кэше - создается новая.
+// Выборки кэшируются под текущей версией; при изменении статей ключ версии удаляется,
+// и следующий запрос создает новую версию - все ранее закэшированные выборки становятся недостижимыми и истекают по TTL
+func (s *Storage) cacheVersion(ctx context.Context, key string) (string, error) {
+	raw, err := s.cache.Get(ctx, key)
+	if err == nil {
+		return string(raw), nil
+	}
+	if !errors.Is(err, cache.ErrDataNotFound) {
+		return "", err
+	}
+
+	version := strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := s.cache.Set(ctx, key, []byte(version), 0); err != nil {
+		return "", err
+	}
+
+	return version, nil
+}
+
+// listKey Ключ страницы списка в кэше: версия + хэш параметров запроса
+func listKey(version string, q models.ArticleListQuery) string {
+	minRating := "-"
+	if q.MinRating != nil {
+		minRating = strconv.FormatFloat(*q.MinRating, 'g', -1, 64)
+	}
+	signature := fmt.Sprintf("sort=%s&desc=%t&limit=%d&offset=%d&after=%d&min_rating=%s&prefix=%s",
+		q.Sort, q.Desc, q.Limit, q.Offset, q.AfterId, minRating, q.TitlePrefix)
+	sum := sha1.Sum([]byte(signature))
+
+	return "list:" + version + ":" + hex.EncodeToString(sum[:])
+}
+
+// selectArticlePage Чтение страницы списка статей из БД
+func (s *Storage) selectArticlePage(ctx context.Context, q models.ArticleListQuery) (models.ArticlePage, error) {
+	defer metrics.ObserveStorage("selectArticlePage", time.Now())
+
+	var (
+		where []string
+		args  []any
+	)
+	if q.MinRating != nil {
+		where = append(where, "r.rating >= ?")
+		args = append(args, *q.MinRating)
+	}
+	if q.TitlePrefix != "" {
+		where = append(where, `a.title LIKE ? ESCAPE '\'`)
+		args = append(args, escapeLike(q.TitlePrefix)+"%")
+	}
+
+	const from = `FROM articles a
+		LEFT JOIN (SELECT article_id, AVG(score) AS rating FROM comments WHERE score IS NOT NULL GROUP BY article_id) r
+		ON r.article_id = a.id`
+
+	filter := ""
+	if len(where) > 0 {
+		filter = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var page models.ArticlePage
+	if err := s.db.GetContext(ctx, &page.Total, "SELECT COUNT(*) "+from+filter, args...); err != nil {
+		return models.ArticlePage{}, fmt.Errorf("count articles: %w", err)
+	}
+
+	dir := "ASC"
+	if q.Desc {
+		dir = "DESC"
+	}
+
+	// Курсор по id работает только при сортировке по id; смещение в этом случае не используется
+	pageArgs := args
+	offset := q.Offset
+	if q.Sort == models.SortById && q.AfterId > 0 {
+		cond := "a.id > ?"
+		if q.Desc {
+			cond = "a.id < ?"
+		}
+		if filter == "" {
+			filter = " WHERE " + cond
+		} else {
+			filter += " AND " + cond
+		}
+		pageArgs = append(pageArgs[:len(args):len(args)], q.AfterId)
+		offset = 0
+	}
+
+	var order string
+	switch q.Sort {
+	case models.SortByTitle:
+		order = "a.title " + dir + ", a.id " + dir
+	case models.SortByRating:
+		// Статьи без оценок - в конце списка при любом направлении сортировки
+		order = "r.rating IS NULL, r.rating " + dir + ", a.id " + dir
+	default:
+		order = "a.id " + dir
+	}
+
+	// Читаем на одну статью больше, чтобы узнать, есть ли следующая страница
+	query := "SELECT a.id, a.title, a.text, r.rating " + from + filter + " ORDER BY " + order + " LIMIT ? OFFSET ?"
+	pageArgs = append(pageArgs, q.Limit+1, offset)
+
+	if err := s.db.SelectContext(ctx, &page.Articles, query, pageArgs...); err != nil {
+		return models.ArticlePage{}, fmt.Errorf("select articles: %w", err)
+	}
+	if page.Articles == nil {
+		page.Articles = []models.ArticleInfo{}
+	}
+
+	if len(page.Articles) > q.Limit {
+		page.Articles = page.Articles[:q.Limit]
+		page.HasMore = true
+		if q.Sort == models.SortById {
+			page.NextId = page.Articles[len(page.Articles)-1].Id
+		}
+	}
+
+	return page, nil
+}
+
+// escapeLike Экранирует спецсимволы шаблона LIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
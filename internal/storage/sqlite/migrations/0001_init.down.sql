@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS comments;
+DROP INDEX IF EXISTS idx_theme;
+DROP TABLE IF EXISTS articles;
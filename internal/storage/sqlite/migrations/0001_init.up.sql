@@ -0,0 +1,15 @@
+-- Исходная схема. IF NOT EXISTS - чтобы миграция применялась и к БД, созданным до появления миграций
+
+-- таблица статей
+CREATE TABLE IF NOT EXISTS articles(
+	id INTEGER PRIMARY KEY,
+	title TEXT NOT NULL UNIQUE,
+	text TEXT NOT NULL);
+CREATE INDEX IF NOT EXISTS idx_theme ON articles(title);
+
+-- таблица комментариев
+CREATE TABLE IF NOT EXISTS comments(
+	id INTEGER PRIMARY KEY,
+	article_id INTEGER NOT NULL,
+	text TEXT NOT NULL,
+	score REAL);
@@ -0,0 +1,2 @@
+-- комментарии выбираются по статье (список комментариев, рейтинг статьи)
+CREATE INDEX IF NOT EXISTS idx_comments_article_id ON comments(article_id);
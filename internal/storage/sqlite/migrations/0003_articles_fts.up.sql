@@ -0,0 +1,25 @@
+-- Полнотекстовый поиск по статьям (FTS5). Требует сборки с тегом sqlite_fts5: go build -tags sqlite_fts5
+-- Индекс хранит только токены (content='articles'), тексты читаются из самой таблицы статей
+CREATE VIRTUAL TABLE IF NOT EXISTS articles_fts USING fts5(
+	title,
+	text,
+	content='articles',
+	content_rowid='id',
+	tokenize='unicode61');
+
+-- индекс поддерживается в актуальном состоянии триггерами
+CREATE TRIGGER IF NOT EXISTS articles_fts_ai AFTER INSERT ON articles BEGIN
+	INSERT INTO articles_fts(rowid, title, text) VALUES (new.id, new.title, new.text);
+END;
+
+CREATE TRIGGER IF NOT EXISTS articles_fts_ad AFTER DELETE ON articles BEGIN
+	INSERT INTO articles_fts(articles_fts, rowid, title, text) VALUES ('delete', old.id, old.title, old.text);
+END;
+
+CREATE TRIGGER IF NOT EXISTS articles_fts_au AFTER UPDATE OF title, text ON articles BEGIN
+	INSERT INTO articles_fts(articles_fts, rowid, title, text) VALUES ('delete', old.id, old.title, old.text);
+	INSERT INTO articles_fts(rowid, title, text) VALUES (new.id, new.title, new.text);
+END;
+
+-- индексируем уже существующие статьи
+INSERT INTO articles_fts(articles_fts) VALUES ('rebuild');
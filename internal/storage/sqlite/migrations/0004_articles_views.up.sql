@@ -0,0 +1,2 @@
+-- счетчик просмотров статьи (накапливается в кэше и периодически переносится в БД)
+ALTER TABLE articles ADD COLUMN views INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,10 @@
+// internal/storage/sqlite/migrations/migrations.go
+
+// SQL-миграции схемы БД. Файлы именуются NNNN_название.up.sql / NNNN_название.down.sql
+// и встраиваются в бинарник, поэтому для миграции достаточно самого приложения
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,116 @@
+// internal/storage/sqlite/random.go
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"test-redis/internal/lib/metrics"
+	"test-redis/internal/models"
+	"test-redis/internal/storage"
+	"time"
+)
+
+// GetRandomData Получить count разных случайных статей.
+// Выбор равновероятный среди существующих статей (пропуски в ид не влияют),
+// при weighted == true вероятность выбора пропорциональна рейтингу статьи.
+// Сами статьи берутся через кэш
+func (s *Storage) GetRandomData(ctx context.Context, count int, weighted bool) ([]models.ArticleInfo, error) {
+	const op = "storage.sqlite.GetRandomData"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if count <= 0 {
+		count = 1
+	}
+
+	var (
+		ids []int64
+		err error
+	)
+	if weighted {
+		ids, err = s.randomIdsByRating(ctx, count)
+	} else {
+		ids, err = s.randomIds(ctx, count)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(ids) == 0 {
+		return nil, storage.ErrDataNotFound
+	}
+
+	articles := make([]models.ArticleInfo, 0, len(ids))
+	for _, id := range ids {
+		article, err := s.getArticle(ctx, id)
+		if errors.Is(err, storage.ErrDataNotFound) {
+			// Статью удалили после выбора ид - просто пропускаем
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		articles = append(articles, article)
+	}
+	if len(articles) == 0 {
+		return nil, storage.ErrDataNotFound
+	}
+
+	return articles, nil
+}
+
+// randomIds Равновероятный выбор count разных ид статей
+func (s *Storage) randomIds(ctx context.Context, count int) ([]int64, error) {
+	defer metrics.ObserveStorage("randomIds", time.Now())
+
+	var ids []int64
+	if err := s.db.SelectContext(ctx, &ids, "SELECT id FROM articles ORDER BY RANDOM() LIMIT ?", count); err != nil {
+		return nil, fmt.Errorf("select random ids: %w", err)
+	}
+
+	return ids, nil
+}
+
+// randomIdsByRating Выбор count разных ид статей с вероятностью, пропорциональной рейтингу
+// (взвешенная выборка без возвращения, алгоритм Efraimidis-Spirakis: ключ u^(1/w), берем count наибольших).
+// Статьи без оценок получают минимальный вес, чтобы тоже иногда попадать в выборку
+func (s *Storage) randomIdsByRating(ctx context.Context, count int) ([]int64, error) {
+	defer metrics.ObserveStorage("randomIdsByRating", time.Now())
+
+	const minWeight = 1
+
+	var rows []struct {
+		Id     int64   `db:"id"`
+		Rating float64 `db:"rating"`
+	}
+	if err := s.db.SelectContext(ctx, &rows, `SELECT a.id, COALESCE(AVG(c.score), 0) AS rating
+		FROM articles a LEFT JOIN comments c ON c.article_id = a.id AND c.score IS NOT NULL
+		GROUP BY a.id`); err != nil {
+		return nil, fmt.Errorf("select ratings: %w", err)
+	}
+
+	type keyed struct {
+		id  int64
+		key float64
+	}
+	keys := make([]keyed, 0, len(rows))
+	for _, row := range rows {
+		weight := math.Max(row.Rating, 0) + minWeight
+		// Сравниваем логарифмы ключей: log(u)/w, так не теряется точность при больших весах
+		keys = append(keys, keyed{id: row.Id, key: math.Log(rand.Float64()) / weight})
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].key > keys[j].key })
+
+	ids := make([]int64, 0, min(count, len(keys)))
+	for _, k := range keys[:min(count, len(keys))] {
+		ids = append(ids, k.id)
+	}
+
+	return ids, nil
+}
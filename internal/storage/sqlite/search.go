@@ -0,0 +1,154 @@
+// internal/storage/sqlite/search.go
+
+package sqlite
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"test-redis/internal/cache"
+	"test-redis/internal/lib/metrics"
+	"test-redis/internal/models"
+	"test-redis/internal/storage"
+	"time"
+)
+
+// searchVersionKey Ключ версии результатов поиска в кэше (см. cacheVersion)
+const searchVersionKey = "search:version"
+
+// Search Полнотекстовый поиск статей (FTS5). Результаты упорядочены по релевантности (bm25, совпадения в заголовке весят больше),
+// совпадения в заголовке и фрагменте текста выделяются тегами <mark> (остальной текст экранируется как HTML).
+// Слова запроса ищутся все сразу (AND), слово с "*" на конце - как префикс.
+// Страницы результатов кэшируются, поэтому повторяющиеся (популярные) запросы не доходят до БД
+func (s *Storage) Search(ctx context.Context, query string, limit int, offset int) (models.SearchPage, error) {
+	const op = "storage.sqlite.Search"
+
+	match := ftsMatchExpr(query)
+	if match == "" {
+		return models.SearchPage{}, storage.ErrEmptyQuery
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	version, err := s.cacheVersion(ctx, searchVersionKey)
+	if err != nil {
+		s.logWarn(ctx, op, "failed to get search cache version, caching skipped", err)
+		page, err := s.selectSearchPage(ctx, match, limit, offset)
+		if err != nil {
+			return models.SearchPage{}, fmt.Errorf("%s: %w", op, err)
+		}
+		return page, nil
+	}
+
+	key := searchKey(version, match, limit, offset)
+	raw, err := s.cache.Get(ctx, key)
+	switch {
+	case err == nil:
+		var page models.SearchPage
+		if err := s.codec.Unmarshal(raw, &page); err != nil {
+			s.logWarn(ctx, op, "can't unmarshal cached page "+key, err)
+			break
+		}
+		return page, nil
+	case !errors.Is(err, cache.ErrDataNotFound):
+		s.logWarn(ctx, op, "failed to get page from cache", err)
+	}
+
+	page, err := s.selectSearchPage(ctx, match, limit, offset)
+	if err != nil {
+		return models.SearchPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if raw, err := s.codec.Marshal(page); err != nil {
+		s.logWarn(ctx, op, "failed to marshal page", err)
+	} else if err := s.cache.Set(ctx, key, raw, 0); err != nil {
+		s.logWarn(ctx, op, "failed to cache page", err)
+	}
+
+	return page, nil
+}
+
+// Границы совпадений, которые расставляет FTS5. Текст статьи экранируется (см. markMatches), поэтому
+// сначала совпадения отмечаются управляющими символами, а теги <mark> подставляются уже после экранирования
+const (
+	matchOpen  = "\x02"
+	matchClose = "\x03"
+)
+
+var matchTags = strings.NewReplacer(matchOpen, "<mark>", matchClose, "</mark>")
+
+// markMatches Экранирует HTML в заголовке или фрагменте текста и выделяет совпадения тегами <mark>
+func markMatches(s string) string {
+	return matchTags.Replace(html.EscapeString(s))
+}
+
+// searchKey Ключ страницы результатов поиска в кэше
+func searchKey(version string, match string, limit int, offset int) string {
+	sum := sha1.Sum([]byte(match + "\x00" + strconv.Itoa(limit) + "\x00" + strconv.Itoa(offset)))
+
+	return "search:" + version + ":" + hex.EncodeToString(sum[:])
+}
+
+// ftsMatchExpr Преобразует пользовательский запрос в выражение MATCH.
+// Каждое слово берется в кавычки, поэтому операторы FTS5 (AND, OR, NEAR, column:...) в запросе не работают и не вызывают синтаксических ошибок
+func ftsMatchExpr(query string) string {
+	var terms []string
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		prefix := strings.HasSuffix(word, "*")
+		word = strings.TrimRight(word, "*")
+		if word == "" {
+			continue
+		}
+
+		term := `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+		if prefix {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// selectSearchPage Поиск в индексе FTS5
+func (s *Storage) selectSearchPage(ctx context.Context, match string, limit int, offset int) (models.SearchPage, error) {
+	defer metrics.ObserveStorage("selectSearchPage", time.Now())
+
+	var page models.SearchPage
+	if err := s.db.GetContext(ctx, &page.Total, "SELECT COUNT(*) FROM articles_fts WHERE articles_fts MATCH ?", match); err != nil {
+		return models.SearchPage{}, fmt.Errorf("count search results: %w", err)
+	}
+
+	// Читаем на одну статью больше, чтобы узнать, есть ли следующая страница
+	err := s.db.SelectContext(ctx, &page.Hits, `SELECT a.id,
+		highlight(articles_fts, 0, char(2), char(3)) AS title,
+		snippet(articles_fts, 1, char(2), char(3), '…', 16) AS snippet,
+		bm25(articles_fts, 10.0, 1.0) AS rank
+		FROM articles_fts JOIN articles a ON a.id = articles_fts.rowid
+		WHERE articles_fts MATCH ?
+		ORDER BY rank, a.id
+		LIMIT ? OFFSET ?`, match, limit+1, offset)
+	if err != nil {
+		return models.SearchPage{}, fmt.Errorf("search articles: %w", err)
+	}
+	if page.Hits == nil {
+		page.Hits = []models.SearchHit{}
+	}
+	for i := range page.Hits {
+		page.Hits[i].Title = markMatches(page.Hits[i].Title)
+		page.Hits[i].Snippet = markMatches(page.Hits[i].Snippet)
+	}
+
+	if len(page.Hits) > limit {
+		page.Hits = page.Hits[:limit]
+		page.HasMore = true
+	}
+
+	return page, nil
+}
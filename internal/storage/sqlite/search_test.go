@@ -0,0 +1,33 @@
+//go:build sqlite_fts5
+
+package sqlite
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSearch_EscapesHTML(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStorage(t)
+
+	if _, err := s.SaveArticle(ctx, `<script>alert(1)</script> redis`, `Tom & Jerry use "redis" <b>daily</b>`); err != nil {
+		t.Fatalf("SaveArticle() error = %v", err)
+	}
+
+	page, err := s.Search(ctx, "redis", 10, 0)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(page.Hits) != 1 {
+		t.Fatalf("Search() hits = %+v, want 1", page.Hits)
+	}
+
+	hit := page.Hits[0]
+	if want := "&lt;script&gt;alert(1)&lt;/script&gt; <mark>redis</mark>"; hit.Title != want {
+		t.Errorf("title = %q, want %q", hit.Title, want)
+	}
+	if want := "Tom &amp; Jerry use &#34;<mark>redis</mark>&#34; &lt;b&gt;daily&lt;/b&gt;"; hit.Snippet != want {
+		t.Errorf("snippet = %q, want %q", hit.Snippet, want)
+	}
+}
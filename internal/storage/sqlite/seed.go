@@ -0,0 +1,135 @@
+// internal/storage/sqlite/seed.go
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"test-redis/internal/lib/metrics"
+	"time"
+)
+
+// SeedOptions Параметры генерации тестовых данных
+type SeedOptions struct {
+	Articles           int   // Количество статей
+	CommentsPerArticle int   // Количество комментариев к каждой статье
+	Seed               int64 // Одинаковое значение дает одинаковые данные
+	BatchSize          int   // Количество строк в одной транзакции
+	Truncate           bool  // Удалить существующие статьи и комментарии перед генерацией
+	WarmCache          bool  // Положить созданные статьи в кэш
+}
+
+// SeedResult Результат генерации
+type SeedResult struct {
+	Articles int
+	Comments int
+	Warmed   int
+}
+
+// seedWords Словарь для текстов статей и комментариев
+var seedWords = strings.Fields(`redis cache storage article comment rating sqlite query index key value ttl
+	cluster sentinel replica latency throughput request response server client memory disk
+	fast slow good bad simple complex useful interesting read write update delete`)
+
+// Seed Генерирует статьи и комментарии. Данные детерминированы значением opts.Seed
+// (при одинаковом исходном состоянии БД), строки вставляются пакетами по opts.BatchSize в одной транзакции.
+// Генерация может быть долгой, поэтому ограничение времени операции хранилища к ней не применяется, прервать ее можно отменой ctx
+func (s *Storage) Seed(ctx context.Context, opts SeedOptions) (SeedResult, error) {
+	const op = "storage.sqlite.Seed"
+	defer metrics.ObserveStorage("Seed", time.Now())
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+
+	var result SeedResult
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	if opts.Truncate {
+		if err := s.truncate(ctx); err != nil {
+			return result, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// Нумерация заголовков продолжается после уже существующих статей, чтобы не нарушить UNIQUE(title)
+	var offset int
+	if err := s.db.GetContext(ctx, &offset, "SELECT COALESCE(MAX(id), 0) FROM articles"); err != nil {
+		return result, fmt.Errorf("%s: select max id: %w", op, err)
+	}
+
+	ids := make([]int64, 0, opts.Articles)
+	batch := newSeedBatch(s, opts.BatchSize)
+	defer batch.rollback()
+
+	for i := 1; i <= opts.Articles; i++ {
+		n := offset + i
+		title := fmt.Sprintf("Title %d", n)
+		text := fmt.Sprintf("This is article %d. %s", n, seedSentence(rng, 20))
+
+		res, err := batch.exec(ctx, "INSERT INTO articles (title, text) VALUES (?, ?)", title, text)
+		if err != nil {
+			return result, fmt.Errorf("%s: insert article: %w", op, err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return result, fmt.Errorf("%s: failed to get last insert id: %w", op, err)
+		}
+		ids = append(ids, id)
+		batch.touch(id)
+		result.Articles++
+
+		for j := 1; j <= opts.CommentsPerArticle; j++ {
+			comment := fmt.Sprintf("comment %d-%d: %s", n, j, seedSentence(rng, 8))
+			score := float64(rng.Intn(101)) // 0..100, как при проверке в API
+
+			if _, err := batch.exec(ctx, "INSERT INTO comments (article_id, text, score) VALUES (?, ?, ?)", id, comment, score); err != nil {
+				return result, fmt.Errorf("%s: insert comment: %w", op, err)
+			}
+			result.Comments++
+		}
+	}
+
+	if err := batch.commit(ctx); err != nil {
+		return result, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if opts.WarmCache {
+		for _, id := range ids {
+			if _, err := s.loadArticle(ctx, id); err != nil {
+				return result, fmt.Errorf("%s: warm cache: %w", op, err)
+			}
+			result.Warmed++
+		}
+	}
+
+	return result, nil
+}
+
+// truncate Удаляет все статьи и комментарии (ид начнутся с 1)
+func (s *Storage) truncate(ctx context.Context) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM comments"); err != nil {
+		return fmt.Errorf("delete comments: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM articles"); err != nil {
+		return fmt.Errorf("delete articles: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// seedSentence Случайная фраза из n слов словаря
+func seedSentence(rng *rand.Rand, n int) string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = seedWords[rng.Intn(len(seedWords))]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,91 @@
+// internal/storage/sqlite/seed_batch.go
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// seedBatch Пакетная вставка: каждые size строк фиксируются отдельной транзакцией.
+// Затронутые статьи удаляются из кэша при фиксации (их ид могли быть закэшированы как отсутствующие)
+type seedBatch struct {
+	s       *Storage
+	size    int
+	tx      *sqlx.Tx
+	rows    int
+	touched []int64
+}
+
+func newSeedBatch(s *Storage, size int) *seedBatch {
+	return &seedBatch{s: s, size: size}
+}
+
+// exec Выполняет запрос в текущей транзакции, при необходимости начиная новую
+func (b *seedBatch) exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if b.tx == nil {
+		tx, err := b.s.db.BeginTxx(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("begin transaction: %w", err)
+		}
+		b.tx = tx
+	}
+
+	res, err := b.tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		b.tx.Rollback()
+		b.tx = nil
+		return nil, err
+	}
+
+	b.rows++
+	if b.rows >= b.size {
+		// Последний результат остается валидным и после фиксации
+		if err := b.commit(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// touch Отмечает статью как измененную в текущем пакете
+func (b *seedBatch) touch(id int64) {
+	b.touched = append(b.touched, id)
+}
+
+// commit Фиксирует текущую транзакцию и сбрасывает кэш затронутых статей (сброс best-effort, см. commitAndInvalidate)
+func (b *seedBatch) commit(ctx context.Context) error {
+	const op = "storage.sqlite.seedBatch.commit"
+
+	if b.tx == nil {
+		return nil
+	}
+
+	err := b.tx.Commit()
+	b.tx = nil
+	b.rows = 0
+	if err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	if len(b.touched) > 0 {
+		if err := b.s.invalidateCommitted(ctx, b.touched...); err != nil {
+			b.s.logWarn(ctx, op, "failed to invalidate seeded articles cache, it is bypassed until the next successful invalidation", err)
+		}
+		b.touched = b.touched[:0]
+	}
+
+	return nil
+}
+
+// rollback Откатывает незафиксированную транзакцию (если она есть)
+func (b *seedBatch) rollback() {
+	if b.tx != nil {
+		b.tx.Rollback()
+		b.tx = nil
+	}
+}
@@ -3,29 +3,56 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/mattn/go-sqlite3"
-	"math/rand"
-	"strconv"
-	"test-redis/internal/cache/redisCache"
+	"github.com/mattn/go-sqlite3"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"test-redis/internal/cache"
+	"test-redis/internal/cache/noopCache"
+	"test-redis/internal/lib/logger/handlers/slogdiscard"
+	"test-redis/internal/lib/logger/sl"
+	"test-redis/internal/lib/metrics"
+	"test-redis/internal/lib/singleflight"
 	"test-redis/internal/models"
 	"test-redis/internal/storage"
+	"test-redis/internal/storage/migrator"
+	"test-redis/internal/storage/sqlite/migrations"
 	"time"
 )
 
 // Storage Структура объекта Storage
 type Storage struct {
 	//	db *sql.DB //из пакета "database/sql"
-	db    *sqlx.DB //из пакета "database/sql"
-	cache *redisCache.Cache
+	db          *sqlx.DB //из пакета "database/sql"
+	cache       cache.Cache
+	boards      cache.SortedSets   // Рейтинги статей (упорядоченные множества кэша)
+	counters    cache.Counters     // Счетчики просмотров; nil - кэш их не поддерживает, просмотры пишутся сразу в БД
+	codec       cache.Codec        // Сериализация значений для кэша
+	negativeTTL atomic.Int64       // Время жизни отметки об отсутствии статьи (time.Duration, может меняться на ходу)
+	timeout     time.Duration      // Ограничение времени одной операции хранилища (0 - без ограничения)
+	log         *slog.Logger       // Ошибки, после которых хранилище продолжает работу (кэш недоступен и т.п.)
+	group       singleflight.Group // Объединение одновременных запросов к БД за одной и той же статьей
+	gens        articleGenerations // Поколения статей: загрузка, начатая до изменения статьи, не попадет в кэш после его сброса
+	stats       cacheStats
+}
+
+// Options Параметры работы хранилища
+type Options struct {
+	Codec       cache.Codec   // Сериализация значений для кэша, по умолчанию JSON
+	NegativeTTL time.Duration // Время жизни отметки об отсутствии статьи (0 - отсутствие не кэшируется)
+	Timeout     time.Duration // Ограничение времени одной операции (запросы к БД и кэшу), 0 - без ограничения
+	Log         *slog.Logger  // По умолчанию сообщения не пишутся
 }
 
 // NewStorage Конструктор объекта Storage
-func NewStorage(storagePath string, cache *redisCache.Cache) (*Storage, error) {
+// Если кэш не передан, используется заглушка noopCache - все запросы идут в БД
+// Время жизни статей в кэше определяется настройками самого кэша (cache.TTLPolicy)
+func NewStorage(storagePath string, c cache.Cache, opts Options) (*Storage, error) {
 	const op = "storage.sqlite.NewStorage" // Имя текущей функции для логов и ошибок
 
 	// Подключаемся к БД (сделал с использованием sqlx - https://github.com/joncrlsn/go-examples/blob/master/sqlx-sqlite.go)
@@ -36,190 +63,510 @@ func NewStorage(storagePath string, cache *redisCache.Cache) (*Storage, error) {
 		//fmt.Println("db connected")
 	}
 
-	// TODO: можно прикрутить миграции, для тренировки
-	// создаем таблицу, если ее еще нет
-	// exec the schema or fail; multi-statement Exec behavior varies between
-	// database drivers0;  pq will exec them all, sqlite3 won't, ymmv
-	var schemaArticles = `
-	--таблица статей
-	--DROP TABLE articles;
-	CREATE TABLE IF NOT EXISTS articles(
-		id INTEGER PRIMARY KEY,
-		title TEXT NOT NULL UNIQUE,
-		text TEXT NOT NULL);
-	CREATE INDEX IF NOT EXISTS idx_theme ON articles(title);
-`
-	db.MustExec(schemaArticles)
-
-	var schemaComments = `
-	--таблица комментариев
-	--DROP TABLE comments;
-	CREATE TABLE IF NOT EXISTS comments(
-		id INTEGER PRIMARY KEY,
-		article_id INTEGER NOT NULL,
-		text TEXT NOT NULL,
-		score REAL);
-`
-	db.MustExec(schemaComments)
-
-	//region Заполнение данными статей
-	//
-	//tx1 := db.MustBegin()
-	//title := ""
-	//article := ""
-	//for i := 1; i < 100; i++ {
-	//	title = fmt.Sprintf("Title %d", i)
-	//	article = fmt.Sprintf("This is article %d", i)
-	//	tx1.MustExec("INSERT INTO articles (title, text) VALUES ($1,$2)", title, article)
-	//}
-	//tx1.Commit()
-	//endregion
-	//region Заполнение данными комментариев
-	//min := 0
-	//max := 100
-	//
-	//tx2 := db.MustBegin()
-	//
-	//comment := ""
-	//for i := 1; i < 100; i++ {
-	//	for j := 1; j < 100; j++ {
-	//		v := rand.Intn(max-min) + min // range is min to max
-	//
-	//		comment = fmt.Sprintf("comment %d-%d", i, j)
-	//		tx2.MustExec("INSERT INTO comments (article_id, text, score) VALUES ($1,$2, $3)", i, comment, v)
-	//	}
-	//}
-	//tx2.Commit()
-
-	//endregion
-
-	//// Тестовый запрос в базу данных, результаты сохраним в слайс []models.ArticleInfo
-	//var articles []models.ArticleInfo
-	//db.Select(&articles, "SELECT * FROM articles ORDER BY title ASC")
-	////выводим первые две
-	//article1, article2 := articles[0], articles[1]
-	//fmt.Printf("Article 1: %#v\nArticle 2: %#v\n", article1, article2)
-
-	return &Storage{db: db, cache: cache}, nil
-}
-
-// getMinArticleId Получить минимальный ИД из таблицы статей
-func (s *Storage) getMinArticleId() (int, error) {
-	const op = "storage.sqlite.getMinArticleId"
-
-	var cnt []int
-	if err := s.db.Select(&cnt, "SELECT MIN(id) FROM articles"); err != nil {
-		return 0, fmt.Errorf("%s: select query: %w", op, err)
-	}
-	if len(cnt) == 0 {
-		return 0, nil
-	}
-
-	return cnt[0], nil
-}
-
-// getMaxArticleId Получить максимальный ИД из таблицы статей
-func (s *Storage) getMaxArticleId() (int, error) {
-	const op = "storage.sqlite.getMaxArticleId"
-
-	var cnt []int
-	if err := s.db.Select(&cnt, "SELECT MAX(id) FROM articles"); err != nil {
-		return 0, fmt.Errorf("%s: select query: %w", op, err)
-	}
-	if len(cnt) == 0 {
-		return 0, nil
-	}
-	return cnt[0], nil
-}
-
-// GetRandomData Получить случайную статью из таблицы
-func (s *Storage) GetRandomData() ([]models.ArticleInfo, error) {
-	const op = "storage.sqlite.GetRandomData"
-
-	//берем случайное число в диапазоне от минимального до максимального ид статьи
-	min := 1
-	max := 100
-	//min, err := s.getMinArticleId()
-	//if err != nil {
-	//	return nil, fmt.Errorf("%s: get min article: %w", op, err)
-	//}
-	//max, err := s.getMaxArticleId()
-	//if err != nil {
-	//	return nil, fmt.Errorf("%s: get max article: %w", op, err)
-	//}
-
-	//собственно случайное значение
-	v := rand.Intn(max-min) + min // range is min to max
-	//v := 2
-
-	if v <= 0 {
-		return nil, fmt.Errorf("%s: there is no data to display (min==max)", op)
-	}
-
-	var result []models.ArticleInfo
-
-	// Сперва поищем в кеше redis
-	result, err := s.cache.GetCachedArticle(strconv.Itoa(v))
-	if err != nil {
-		fmt.Println(time.Now(), err)
-	}
-
-	// Если ничего не найдено, увеличиваем ид, и так 100 раз, потом выходим
-	if result == nil {
-		isFound := false
-		counter := 0
-		for !isFound || counter > 100 {
-			if err := s.db.Select(&result, "SELECT id, title, text, (SELECT AVG(score) FROM comments WHERE score IS NOT NULL AND article_id= $1) as rating FROM articles WHERE id= $1", v); err != nil {
-				return nil, fmt.Errorf("%s: select: %w", op, err)
-			}
-
-			if len(result) > 0 {
-				isFound = true
-
-				// Пишем найденное в кэш
-				fmt.Println("set value to cache")
+	// Схема БД создается миграциями (команда migrate), здесь только проверяем, что она актуальна
+	if err := checkSchema(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-				// преобразуем структуру в []byte для хранения в кэше
-				res, err := json.Marshal(result)
-				if err != nil {
-					// TODO - разобраться с ошибкой
-					return result, fmt.Errorf("%s: cannot set value to cache: %w", op, err)
-				} else {
-					s.cache.SetCachedArticle(strconv.Itoa(v), res)
-				}
-			} else {
-				counter++
-				v++
-			}
-		}
+	if c == nil {
+		c = noopCache.NewCache()
+	}
+	// Кэш без упорядоченных множеств - рейтинги не ведутся
+	boards, ok := c.(cache.SortedSets)
+	if !ok {
+		boards = noopCache.NewCache()
+	}
+	counters, _ := c.(cache.Counters)
+	if opts.Codec == nil {
+		opts.Codec = cache.JSONCodec{}
 	}
+	if opts.Log == nil {
+		opts.Log = slogdiscard.NewDiscardLogger()
+	}
+
+	s := &Storage{db: db, cache: c, boards: boards, counters: counters, codec: opts.Codec, timeout: opts.Timeout, log: opts.Log.With(slog.String("component", "storage/sqlite"))}
+	s.SetNegativeTTL(opts.NegativeTTL)
+
+	return s, nil
+}
 
-	return result, nil
+// SetNegativeTTL Замена времени жизни отметки об отсутствии статьи (перечитывание конфигурации)
+func (s *Storage) SetNegativeTTL(ttl time.Duration) {
+	s.negativeTTL.Store(int64(ttl))
 }
 
-// GetData - получить данные
-func (s *Storage) GetData(id string) (string, error) {
+// NewMigrator Открывает БД для применения миграций (команда migrate). Соединение закрывается методом Close мигратора
+func NewMigrator(storagePath string) (*migrator.Migrator, error) {
+	const op = "storage.sqlite.NewMigrator"
+
+	db, err := sqlx.Connect("sqlite3", storagePath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	m, err := migrator.New(db, migrations.FS)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return m, nil
+}
+
+// checkSchema Проверяет, что к БД применены все миграции, известные этой версии приложения
+func checkSchema(db *sqlx.DB) error {
+	m, err := migrator.New(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	version, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if version != m.Latest() {
+		return fmt.Errorf("%w: database version %d, expected %d (run `migrate up`)", storage.ErrSchemaOutdated, version, m.Latest())
+	}
+
+	return nil
+}
+
+// withTimeout Ограничивает время операции хранилища. Операция прерывается и при отмене ctx (клиент отключился, сервер останавливается)
+func (s *Storage) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, s.timeout)
+}
+
+// detached Контекст для действий, которые нельзя прерывать из-за отмены запроса: обновление кэша после фиксации транзакции,
+// загрузка статьи, которую ждут несколько запросов. Время выполнения по-прежнему ограничено
+func (s *Storage) detached(ctx context.Context) (context.Context, context.CancelFunc) {
+	return s.withTimeout(context.WithoutCancel(ctx))
+}
+
+// logWarn Ошибка, после которой операция продолжается (например, кэш недоступен - данные берутся из БД)
+func (s *Storage) logWarn(ctx context.Context, op string, msg string, err error) {
+	s.log.Warn(msg, slog.String("op", op), sl.Err(err), sl.RequestID(ctx))
+}
+
+// logError Ошибка, из-за которой данные могут быть потеряны или рассогласованы
+func (s *Storage) logError(ctx context.Context, op string, msg string, err error) {
+	s.log.Error(msg, slog.String("op", op), sl.Err(err), sl.RequestID(ctx))
+}
+
+// Ping Проверка доступности БД
+func (s *Storage) Ping(ctx context.Context) error {
+	const op = "storage.sqlite.Ping"
+
+	var one int
+	if err := s.db.GetContext(ctx, &one, "SELECT 1"); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Close Закрывает соединение с БД. Кэш, переданный в NewStorage, не закрывается - им владеет вызывающая сторона
+func (s *Storage) Close() error {
+	const op = "storage.sqlite.Close"
+
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetData Получить статью (вместе с рейтингом) по ее ид
+func (s *Storage) GetData(ctx context.Context, id int64) (models.ArticleInfo, error) {
 	const op = "storage.sqlite.GetData"
 
-	// Подготавливаем запрос (проверка корректности синтаксиса)
-	stmt, err := s.db.Prepare("SELECT text FROM articles WHERE id = ?")
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	article, err := s.getArticle(ctx, id)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return models.ArticleInfo{}, storage.ErrDataNotFound
+	}
+	if err != nil {
+		return models.ArticleInfo{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return article, nil
+}
+
+// getArticle Получение статьи по схеме cache-aside: ищем в кэше, при промахе читаем из БД и кладем в кэш.
+// Одновременные промахи по одной статье объединяются в один запрос к БД,
+// отсутствие статьи тоже кэшируется (на короткое время negativeTTL).
+// Ошибки кэша не мешают вернуть данные из БД
+func (s *Storage) getArticle(ctx context.Context, id int64) (models.ArticleInfo, error) {
+	const op = "storage.sqlite.getArticle"
+
+	var (
+		article models.ArticleInfo
+		err     error = cache.ErrDataNotFound
+	)
+	// Если сброс кэша после последнего изменения статьи не удался, в кэше может быть устаревшее значение
+	if !s.articleCacheStale(id) {
+		article, err = s.getCachedArticle(ctx, id)
+	}
+	switch {
+	case err == nil:
+		s.stats.cacheHits.Add(1)
+		return article, nil
+	case errors.Is(err, storage.ErrDataNotFound):
+		s.stats.negativeHits.Add(1)
+		return models.ArticleInfo{}, storage.ErrDataNotFound
+	case errors.Is(err, cache.ErrDataNotFound):
+		s.stats.cacheMisses.Add(1)
+	default:
+		s.stats.cacheMisses.Add(1)
+		s.logWarn(ctx, op, "failed to get article from cache", err)
+	}
+
+	// Результат загрузки получат все ожидающие запросы, поэтому отключение клиента, начавшего ее, загрузку не прерывает
+	v, err, shared := s.group.Do(articleKey(id), func() (any, error) {
+		ctx, cancel := s.detached(ctx)
+		defer cancel()
+		return s.loadArticle(ctx, id)
+	})
+	if shared {
+		s.stats.coalesced.Add(1)
+	}
 	if err != nil {
-		return "", fmt.Errorf("%s: prepare statement: %w", op, err)
+		return models.ArticleInfo{}, err
+	}
+
+	return v.(models.ArticleInfo), nil
+}
+
+// loadArticle Читает статью из БД и кладет результат в кэш (в том числе отметку об отсутствии статьи)
+func (s *Storage) loadArticle(ctx context.Context, id int64) (models.ArticleInfo, error) {
+	const op = "storage.sqlite.loadArticle"
+
+	s.stats.dbQueries.Add(1)
+
+	gen := s.articleGen(id)
+	article, err := s.selectArticle(ctx, id)
+	found := err == nil
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return models.ArticleInfo{}, err
+	}
+
+	if err := s.cacheLoaded(ctx, id, gen, article, found); err != nil {
+		s.logWarn(ctx, op, "failed to cache article", err)
 	}
 
-	var result string
+	return article, err
+}
+
+// CacheStats Счетчики работы кэша статей
+func (s *Storage) CacheStats() models.CacheStats {
+	return s.stats.snapshot()
+}
+
+// selectArticle Чтение статьи из БД. Рейтинг - средняя оценка в комментариях
+func (s *Storage) selectArticle(ctx context.Context, id int64) (models.ArticleInfo, error) {
+	// Запросы к БД за статьей для GetData и GetRandomData (попадания в кэш сюда не доходят)
+	defer metrics.ObserveStorage("selectArticle", time.Now())
 
-	//в параметрах используем указатель, чтобы получить результаты
-	err = stmt.QueryRow(id).Scan(&result)
+	var article models.ArticleInfo
 
-	//если строки не найдено - возвращаем пустую строку
+	err := s.db.GetContext(ctx, &article, `SELECT id, title, text,
+		(SELECT AVG(score) FROM comments WHERE score IS NOT NULL AND article_id = articles.id) AS rating
+		FROM articles WHERE id = ?`, id)
 	if errors.Is(err, sql.ErrNoRows) {
-		return "", storage.ErrDataNotFound
+		return models.ArticleInfo{}, storage.ErrDataNotFound
+	}
+	if err != nil {
+		return models.ArticleInfo{}, fmt.Errorf("select article: %w", err)
+	}
+
+	return article, nil
+}
+
+// SaveArticle Добавить новую статью. Возвращает ид созданной статьи
+func (s *Storage) SaveArticle(ctx context.Context, title string, text string) (int64, error) {
+	const op = "storage.sqlite.SaveArticle"
+	defer metrics.ObserveStorage("SaveArticle", time.Now())
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "INSERT INTO articles (title, text) VALUES (?, ?)", title, text)
+	if err != nil {
+		// Статья с таким заголовком уже существует (title UNIQUE)
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrDataExists)
+		}
+		return 0, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get last insert id: %w", op, err)
+	}
+
+	// Ид мог остаться в кэше с отметкой "статьи нет" (ид удаленных статей переиспользуются)
+	if err := s.commitAndInvalidate(ctx, tx, id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// UpdateArticle Изменить статью. Поля со значением nil остаются без изменений
+func (s *Storage) UpdateArticle(ctx context.Context, id int64, title *string, text *string) error {
+	const op = "storage.sqlite.UpdateArticle"
+	defer metrics.ObserveStorage("UpdateArticle", time.Now())
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var (
+		fields []string
+		args   []any
+	)
+	if title != nil {
+		fields = append(fields, "title = ?")
+		args = append(args, *title)
 	}
+	if text != nil {
+		fields = append(fields, "text = ?")
+		args = append(args, *text)
+	}
+
+	// Менять нечего - достаточно проверить, что статья существует
+	if len(fields) == 0 {
+		exists, err := articleExists(ctx, s.db, id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if !exists {
+			return storage.ErrDataNotFound
+		}
+		return nil
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	args = append(args, id)
+	res, err := tx.ExecContext(ctx, "UPDATE articles SET "+strings.Join(fields, ", ")+" WHERE id = ?", args...)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%s: %w", op, storage.ErrDataExists)
+		}
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+	}
+	if affected == 0 {
+		return storage.ErrDataNotFound
+	}
+
+	if err := s.commitAndInvalidate(ctx, tx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteArticle Удалить статью вместе с ее комментариями
+func (s *Storage) DeleteArticle(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.DeleteArticle"
+	defer metrics.ObserveStorage("DeleteArticle", time.Now())
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
 
+	res, err := tx.ExecContext(ctx, "DELETE FROM articles WHERE id = ?", id)
 	if err != nil {
-		return "", fmt.Errorf("%s: execute statement: %w", op, err)
+		return fmt.Errorf("%s: delete article: %w", op, err)
 	}
 
-	return result, nil
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+	}
+	if affected == 0 {
+		return storage.ErrDataNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM comments WHERE article_id = ?", id); err != nil {
+		return fmt.Errorf("%s: delete comments: %w", op, err)
+	}
+
+	if err := s.commitAndInvalidate(ctx, tx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.removeFromBoards(ctx, id)
+
+	return nil
+}
+
+// GetComments Получить комментарии к статье постранично. Возвращает также общее количество комментариев
+func (s *Storage) GetComments(ctx context.Context, articleId int64, limit int, offset int) ([]models.Comment, int, error) {
+	const op = "storage.sqlite.GetComments"
+	defer metrics.ObserveStorage("GetComments", time.Now())
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	exists, err := articleExists(ctx, s.db, articleId)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return nil, 0, storage.ErrDataNotFound
+	}
+
+	var total int
+	if err := s.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM comments WHERE article_id = ?", articleId); err != nil {
+		return nil, 0, fmt.Errorf("%s: count: %w", op, err)
+	}
+
+	comments := make([]models.Comment, 0, limit)
+	if err := s.db.SelectContext(ctx, &comments, "SELECT id, article_id, text, score FROM comments WHERE article_id = ? ORDER BY id LIMIT ? OFFSET ?", articleId, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("%s: select: %w", op, err)
+	}
+
+	return comments, total, nil
+}
+
+// SaveComment Добавить комментарий к статье. Рейтинг статьи меняется, поэтому она удаляется из кэша
+func (s *Storage) SaveComment(ctx context.Context, articleId int64, text string, score *float64) (int64, error) {
+	const op = "storage.sqlite.SaveComment"
+	defer metrics.ObserveStorage("SaveComment", time.Now())
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	exists, err := articleExists(ctx, tx, articleId)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return 0, storage.ErrDataNotFound
+	}
+
+	res, err := tx.ExecContext(ctx, "INSERT INTO comments (article_id, text, score) VALUES (?, ?, ?)", articleId, text, score)
+	if err != nil {
+		return 0, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get last insert id: %w", op, err)
+	}
+
+	if err := s.commitAndInvalidate(ctx, tx, articleId); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.recordComment(ctx, articleId, score)
+
+	return id, nil
+}
+
+// DeleteComment Удалить комментарий к статье
+func (s *Storage) DeleteComment(ctx context.Context, articleId int64, commentId int64) error {
+	const op = "storage.sqlite.DeleteComment"
+	defer metrics.ObserveStorage("DeleteComment", time.Now())
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM comments WHERE id = ? AND article_id = ?", commentId, articleId)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+	}
+	if affected == 0 {
+		return storage.ErrDataNotFound
+	}
+
+	if err := s.commitAndInvalidate(ctx, tx, articleId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Суточные суммы оценок не корректируются (неизвестно, когда был оставлен комментарий), обновляется только средний рейтинг
+	s.syncRating(ctx, articleId)
+
+	return nil
+}
+
+// articleExists Проверяет наличие статьи (в БД или в рамках транзакции)
+func articleExists(ctx context.Context, q sqlx.QueryerContext, articleId int64) (bool, error) {
+	var exists bool
+	if err := sqlx.GetContext(ctx, q, &exists, "SELECT EXISTS(SELECT 1 FROM articles WHERE id = ?)", articleId); err != nil {
+		return false, fmt.Errorf("check article exists: %w", err)
+	}
+
+	return exists, nil
+}
+
+// commitAndInvalidate Фиксирует транзакцию, изменившую статью (или ее комментарии), и сбрасывает кэш статьи.
+// Кэш сбрасывается только после коммита: недоступность кэша не должна мешать записи (работа без Redis, startup_policy: degrade).
+// Загрузки статьи, начатые до коммита, в кэш уже не попадут (см. articleGenerations). Если сбросить не удалось,
+// кэш статьи не читается, пока сброс не удастся при следующей загрузке. Отмена запроса после коммита сброс не прерывает
+func (s *Storage) commitAndInvalidate(ctx context.Context, tx *sqlx.Tx, articleId int64) error {
+	const op = "storage.sqlite.commitAndInvalidate"
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	ctx, cancel := s.detached(ctx)
+	defer cancel()
+
+	if err := s.invalidateCommitted(ctx, articleId); err != nil {
+		s.logWarn(ctx, op, "failed to invalidate article cache, it is bypassed until the next successful invalidation", err)
+	}
+
+	return nil
+}
+
+// isUniqueViolation Проверяет, что ошибка - нарушение ограничения UNIQUE
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
 }
@@ -0,0 +1,49 @@
+//go:build sqlite_fts5
+
+package sqlite
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"test-redis/internal/cache"
+	"test-redis/internal/cache/memoryCache"
+)
+
+// newTestStorage Хранилище с актуальной схемой во временной БД и кэшем в памяти.
+// Миграция 0003 создает таблицу FTS5, поэтому тесты хранилища собираются только с тегом: go test -tags sqlite_fts5 ./...
+func newTestStorage(t *testing.T) (*Storage, *memoryCache.Cache) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	m, err := NewMigrator(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	m.Close()
+
+	c := memoryCache.NewCache(1000, cache.TTLPolicy{})
+	s, err := NewStorage(path, c, Options{})
+	if err != nil {
+		t.Fatalf("NewStorage() error = %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	return s, c
+}
+
+// insertArticles Статьи с ид 1..n
+func insertArticles(t *testing.T, s *Storage, n int) {
+	t.Helper()
+
+	for i := 1; i <= n; i++ {
+		if _, err := s.db.Exec("INSERT INTO articles(id, title, text) VALUES (?, ?, ?)", i, fmt.Sprintf("title %d", i), "text"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+// internal/storage/sqlite/views.go
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"test-redis/internal/cache"
+	"test-redis/internal/lib/logger/sl"
+	"test-redis/internal/lib/metrics"
+	"test-redis/internal/storage"
+	"time"
+)
+
+// Просмотры статей накапливаются в счетчиках кэша (INCR на каждый просмотр, без записи в БД),
+// ид статей с ненулевым счетчиком - в множестве viewsDirtyKey. Фоновый процесс (RunViewsFlusher)
+// периодически переносит накопленные значения в колонку articles.views
+const (
+	viewsDirtyKey   = "views:dirty"
+	viewsFlushBatch = 500 // Сколько статей переносится за одну транзакцию
+
+	defaultViewsFlushInterval = 10 * time.Second
+)
+
+// viewsKey Счетчик просмотров статьи, еще не перенесенных в БД
+func viewsKey(id int64) string {
+	return "views:" + strconv.FormatInt(id, 10)
+}
+
+// RecordView Учесть просмотр статьи: счетчик просмотров и рейтинг активности.
+// Если кэш не поддерживает счетчики или недоступен, просмотр сразу записывается в БД
+func (s *Storage) RecordView(ctx context.Context, articleId int64) error {
+	const op = "storage.sqlite.RecordView"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if err := s.countView(ctx, articleId); err != nil {
+		s.logWarn(ctx, op, "failed to count view in cache, writing to db", err)
+		if err := s.addViews(ctx, articleId, 1); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := s.recordViewActivity(ctx, articleId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// countView Увеличивает счетчик просмотров в кэше. Порядок важен: сначала счетчик, потом отметка в множестве,
+// тогда просмотр, пришедший во время переноса, попадет либо в текущий перенос, либо в следующий
+func (s *Storage) countView(ctx context.Context, articleId int64) error {
+	if s.counters == nil {
+		return errors.New("cache does not support counters")
+	}
+
+	if _, err := s.counters.IncrBy(ctx, viewsKey(articleId), 1); err != nil {
+		return err
+	}
+
+	return s.counters.SAdd(ctx, viewsDirtyKey, strconv.FormatInt(articleId, 10))
+}
+
+// addViews Прибавить просмотры к счетчику статьи в БД
+func (s *Storage) addViews(ctx context.Context, articleId int64, n int64) error {
+	if _, err := s.db.ExecContext(ctx, "UPDATE articles SET views = views + ? WHERE id = ?", n, articleId); err != nil {
+		return fmt.Errorf("update views: %w", err)
+	}
+
+	return nil
+}
+
+// GetViews Количество просмотров статьи: сохраненные в БД и еще не перенесенные из кэша
+func (s *Storage) GetViews(ctx context.Context, articleId int64) (int64, error) {
+	const op = "storage.sqlite.GetViews"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var views int64
+	err := s.db.GetContext(ctx, &views, "SELECT views FROM articles WHERE id = ?", articleId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, storage.ErrDataNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%s: select views: %w", op, err)
+	}
+
+	if s.counters == nil {
+		return views, nil
+	}
+
+	raw, err := s.cache.Get(ctx, viewsKey(articleId))
+	if errors.Is(err, cache.ErrDataNotFound) {
+		return views, nil
+	}
+	if err != nil {
+		// Кэш недоступен - отдаем то, что уже есть в БД
+		s.logWarn(ctx, op, "failed to get views from cache", err)
+		return views, nil
+	}
+
+	pending, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: parse pending views: %w", op, err)
+	}
+
+	return views + pending, nil
+}
+
+// FlushViews Перенести накопленные в кэше просмотры в БД. Возвращает количество обновленных статей.
+// Время ограничено для каждой порции статей, а не для всего переноса
+func (s *Storage) FlushViews(ctx context.Context) (int, error) {
+	const op = "storage.sqlite.FlushViews"
+
+	if s.counters == nil {
+		return 0, nil
+	}
+
+	var flushed int
+	for {
+		n, err := s.flushViewsBatch(ctx)
+		flushed += n
+		if err != nil {
+			return flushed, fmt.Errorf("%s: %w", op, err)
+		}
+		if n < viewsFlushBatch {
+			return flushed, nil
+		}
+	}
+}
+
+// flushViewsBatch Перенос просмотров для очередной порции статей.
+// Если записать в БД не удалось, счетчики возвращаются в кэш
+func (s *Storage) flushViewsBatch(ctx context.Context) (int, error) {
+	const op = "storage.sqlite.flushViewsBatch"
+
+	defer metrics.ObserveStorage("flushViewsBatch", time.Now())
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	members, err := s.counters.SPop(ctx, viewsDirtyKey, viewsFlushBatch)
+	if err != nil {
+		return 0, err
+	}
+	if len(members) == 0 {
+		return 0, nil
+	}
+
+	pending := make(map[int64]int64, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m, 10, 64)
+		if err != nil {
+			continue
+		}
+		n, err := s.counters.GetDel(ctx, viewsKey(id))
+		if err != nil {
+			// Счетчики оставшихся статей не тронуты, достаточно вернуть их отметки
+			s.restoreViews(ctx, pending)
+			ctx, cancel := s.detached(ctx)
+			defer cancel()
+			if err := s.counters.SAdd(ctx, viewsDirtyKey, members...); err != nil {
+				// Счетчики остались в кэше, но без отметок они не будут перенесены в БД
+				s.logError(ctx, op, "failed to restore dirty views marks", err)
+			}
+			return 0, err
+		}
+		if n > 0 {
+			pending[id] = n
+		}
+	}
+
+	if err := s.saveViews(ctx, pending); err != nil {
+		s.restoreViews(ctx, pending)
+		return 0, err
+	}
+
+	return len(members), nil
+}
+
+// saveViews Записывает просмотры в БД одной транзакцией
+func (s *Storage) saveViews(ctx context.Context, pending map[int64]int64) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for id, n := range pending {
+		if _, err := tx.ExecContext(ctx, "UPDATE articles SET views = views + ? WHERE id = ?", n, id); err != nil {
+			return fmt.Errorf("update views of article %d: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// restoreViews Возвращает извлеченные счетчики в кэш, чтобы просмотры не потерялись (даже если перенос был прерван отменой ctx)
+func (s *Storage) restoreViews(ctx context.Context, pending map[int64]int64) {
+	const op = "storage.sqlite.restoreViews"
+
+	ctx, cancel := s.detached(ctx)
+	defer cancel()
+	for id, n := range pending {
+		if _, err := s.counters.IncrBy(ctx, viewsKey(id), n); err != nil {
+			// Просмотры уже сняты из кэша и не записаны в БД - они потеряны
+			s.log.Error("failed to restore views, views lost", slog.String("op", op), slog.Int64("article_id", id), slog.Int64("views", n), sl.Err(err))
+			continue
+		}
+		if err := s.counters.SAdd(ctx, viewsDirtyKey, strconv.FormatInt(id, 10)); err != nil {
+			s.logError(ctx, op, "failed to restore dirty views mark", err)
+		}
+	}
+}
+
+// RunViewsFlusher Периодически переносит просмотры в БД, пока не отменен ctx; перед выходом выполняет последний перенос
+// (он уже не зависит от отмены ctx)
+func (s *Storage) RunViewsFlusher(ctx context.Context, interval time.Duration) {
+	const op = "storage.sqlite.RunViewsFlusher"
+
+	if interval <= 0 {
+		interval = defaultViewsFlushInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.FlushViews(ctx); err != nil {
+				s.logError(ctx, op, "failed to flush views", err)
+			}
+		case <-ctx.Done():
+			if _, err := s.FlushViews(context.WithoutCancel(ctx)); err != nil {
+				s.logError(ctx, op, "failed to flush views on stop", err)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,139 @@
+//go:build sqlite_fts5
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"test-redis/internal/cache"
+)
+
+func TestFlushViews(t *testing.T) {
+	tests := []struct {
+		name       string
+		views      map[int64]int // просмотры по статьям до переноса
+		failDB     bool          // запись в БД завершается ошибкой
+		wantDB     map[int64]int64
+		wantCached map[int64]int64 // просмотры, оставшиеся в кэше
+	}{
+		{
+			name:       "nothing to flush",
+			wantDB:     map[int64]int64{1: 0, 2: 0},
+			wantCached: map[int64]int64{},
+		},
+		{
+			name:       "flushed to db",
+			views:      map[int64]int{1: 3, 2: 1},
+			wantDB:     map[int64]int64{1: 3, 2: 1},
+			wantCached: map[int64]int64{},
+		},
+		{
+			name:       "restored to cache when db fails",
+			views:      map[int64]int{1: 3, 2: 1},
+			failDB:     true,
+			wantDB:     map[int64]int64{1: 0, 2: 0},
+			wantCached: map[int64]int64{1: 3, 2: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, c := newTestStorage(t)
+			insertArticles(t, s, 2)
+
+			for id, n := range tt.views {
+				for range n {
+					if err := s.RecordView(ctx, id); err != nil {
+						t.Fatalf("RecordView(%d) error = %v", id, err)
+					}
+				}
+			}
+			if tt.failDB {
+				s.db.MustExec("CREATE TRIGGER fail_views BEFORE UPDATE OF views ON articles BEGIN SELECT RAISE(ABORT, 'views are read-only'); END")
+			}
+
+			_, err := s.FlushViews(ctx)
+			if (err != nil) != tt.failDB {
+				t.Fatalf("FlushViews() error = %v, want error %v", err, tt.failDB)
+			}
+
+			for id, want := range tt.wantDB {
+				var views int64
+				if err := s.db.Get(&views, "SELECT views FROM articles WHERE id = ?", id); err != nil {
+					t.Fatal(err)
+				}
+				if views != want {
+					t.Errorf("article %d: views in db = %d, want %d", id, views, want)
+				}
+			}
+			for id := range tt.wantDB {
+				n, err := c.GetDel(ctx, viewsKey(id))
+				if err != nil && !errors.Is(err, cache.ErrDataNotFound) {
+					t.Fatal(err)
+				}
+				if want := tt.wantCached[id]; n != want {
+					t.Errorf("article %d: views in cache = %d, want %d", id, n, want)
+				}
+				// Вернуть счетчик, чтобы проверить повторный перенос
+				if n > 0 {
+					c.IncrBy(ctx, viewsKey(id), n)
+				}
+			}
+
+			if !tt.failDB {
+				return
+			}
+
+			// После восстановления БД просмотры переносятся следующим переносом
+			s.db.MustExec("DROP TRIGGER fail_views")
+			if _, err := s.FlushViews(ctx); err != nil {
+				t.Fatalf("FlushViews() after restore error = %v", err)
+			}
+			for id, want := range tt.wantCached {
+				total, err := s.GetViews(ctx, id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if total != want {
+					t.Errorf("article %d: views = %d, want %d", id, total, want)
+				}
+				var views int64
+				if err := s.db.Get(&views, "SELECT views FROM articles WHERE id = ?", id); err != nil {
+					t.Fatal(err)
+				}
+				if views != want {
+					t.Errorf("article %d: views in db after restore = %d, want %d", id, views, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetViews_CountsPending(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStorage(t)
+	insertArticles(t, s, 1)
+
+	for range 2 {
+		if err := s.RecordView(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.FlushViews(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordView(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	views, err := s.GetViews(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetViews() error = %v", err)
+	}
+	if views != 3 {
+		t.Errorf("GetViews() = %d, want 3 (2 in db + 1 pending)", views)
+	}
+}
@@ -5,6 +5,8 @@ package storage
 import "errors"
 
 var (
-	ErrDataNotFound = errors.New("data not found")
-	ErrURLExists    = errors.New("data exists")
+	ErrDataNotFound   = errors.New("data not found")
+	ErrDataExists     = errors.New("data exists") // Нарушение уникальности (например, статья с таким заголовком уже есть)
+	ErrSchemaOutdated = errors.New("database schema is out of date")
+	ErrEmptyQuery     = errors.New("empty search query") // Поисковый запрос не содержит ни одного слова
 )